@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"segment"
+	"segment/address"
+)
+
+// 解析命令行参数中的地址，没有参数时逐行读取标准输入，每个地址输出一行 JSON
+func parseAddress(args []string) error {
+	fs := flag.NewFlagSet("address", flag.ExitOnError)
+	dicts := fs.String("dicts", "./dicts", "词典目录")
+	divisions := fs.String("divisions", "", "行政区划表，默认为词典目录中的 "+address.DivisionFileName+"，不存在时只按后缀识别省市区县，这时不能识别简称")
+	fs.Parse(args)
+
+	seg := segment.NewSegment()
+	if err := seg.Init(*dicts); err != nil {
+		return err
+	}
+
+	file := *divisions
+	if file == "" {
+		file = *dicts + "/" + address.DivisionFileName
+	}
+	var table *address.Divisions
+	if _, err := os.Stat(file); err == nil || *divisions != "" {
+		if table, err = address.LoadDivisions(file); err != nil {
+			return err
+		}
+	}
+	parser := address.NewParser(seg, table)
+
+	handle := func(text string) error {
+		data, err := json.Marshal(parser.Parse(text))
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if fs.NArg() > 0 {
+		for _, text := range fs.Args() {
+			if err := handle(text); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := handle(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
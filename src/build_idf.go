@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io/ioutil"
+	"segment"
+	"segment/keywords"
+	"segment/utils"
+)
+
+// 从文档集合生成关键词提取用的 IDF 表，默认每个文件是一篇文档
+func buildIdf(args []string) error {
+	fs := flag.NewFlagSet("build-idf", flag.ExitOnError)
+	dicts := fs.String("dicts", "./dicts", "词典目录")
+	options := fs.String("options", "", "在关键词提取默认分词选项基础上修改的 MatchOptions，要和提取关键词时相同")
+	output := fs.String("output", "./dicts/"+keywords.IDFFileName, "IDF 表输出文件")
+	lines := fs.Bool("lines", false, "文件中每一行是一篇文档")
+	minDF := fs.Int("mindf", 1, "出现的文档数少于这个值的词不写入 IDF 表")
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("build-idf: missing document files")
+	}
+
+	seg := segment.NewSegment()
+	if err := seg.Init(*dicts); err != nil {
+		return err
+	}
+	builder := keywords.NewIDFBuilder(seg)
+	builder.MinDF = *minDF
+	if err := parseMatchOptions(*options, builder.Options); err != nil {
+		return err
+	}
+	if err := checkStopWordLists(seg, builder.Options); err != nil {
+		return err
+	}
+
+	for _, file := range fs.Args() {
+		if *lines {
+			err := utils.EachLine(file, func(line string) {
+				if len(line) > 0 {
+					builder.Add(line)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		builder.Add(string(data))
+	}
+	return builder.Save(*output)
+}
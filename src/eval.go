@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"segment"
+	"segment/eval"
+	"segment/match"
+	"segment/utils"
+	"strings"
+)
+
+// 用 SIGHAN 格式的标准答案评测分词的准确率
+func evalSegment(args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ExitOnError)
+	gold := fs.String("gold", "", "标准答案，一行一句，词之间以空格分割")
+	dicts := fs.String("dicts", "./dicts", "词典目录")
+	words := fs.String("words", "", "判断未登录词用的词表，一行一个词，为空时使用分词词典")
+	options := fs.String("options", "", "MatchOptions 开关，如 Matcher=forward,ChineseNameIdentify=false")
+	top := fs.Int("top", 20, "输出出现次数最多的错误数")
+	report := fs.String("report", "", "JSON 格式评测报告的输出文件")
+	fs.Parse(args)
+
+	if *gold == "" {
+		fs.Usage()
+		return errors.New("eval: missing -gold")
+	}
+
+	// 评测时只取一种切分结果，停用词也要参与评测
+	opt := match.NewMatchOptions()
+	opt.MultiDimensionality = false
+	opt.FilterStopWords = false
+	if err := parseMatchOptions(*options, opt); err != nil {
+		return err
+	}
+
+	seg := segment.NewSegment()
+	if err := seg.Init(*dicts); err != nil {
+		return err
+	}
+	if err := checkStopWordLists(seg, opt); err != nil {
+		return err
+	}
+
+	isKnown := func(word string) bool {
+		return seg.WordDictionary().GetWordAttr(utils.ToRunes(word)) != nil
+	}
+	if *words != "" {
+		vocab := make(map[string]bool)
+		if err := utils.EachLine(*words, func(line string) {
+			for _, word := range strings.Fields(line) {
+				vocab[word] = true
+			}
+		}); err != nil {
+			return err
+		}
+		isKnown = func(word string) bool {
+			return vocab[word]
+		}
+	}
+
+	result := eval.NewResult()
+	err := utils.EachLine(*gold, func(line string) {
+		goldWords := strings.Fields(strings.TrimPrefix(line, "\ufeff"))
+		if len(goldWords) == 0 {
+			return
+		}
+		text := strings.Join(goldWords, "")
+		predicted := eval.Tokens(seg.DoSegmentWithOption(text, opt), text)
+		result.Add(goldWords, predicted, isKnown)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("lines      %d\n", result.Lines)
+	fmt.Printf("gold       %d\n", result.Gold)
+	fmt.Printf("predicted  %d\n", result.Predicted)
+	fmt.Printf("correct    %d\n", result.Correct)
+	fmt.Printf("precision  %.4f\n", result.Precision())
+	fmt.Printf("recall     %.4f\n", result.Recall())
+	fmt.Printf("f1         %.4f\n", result.F1())
+	fmt.Printf("oov rate   %.4f\n", result.OOVRate())
+	fmt.Printf("oov recall %.4f\n", result.OOVRecall())
+	fmt.Printf("iv recall  %.4f\n", result.IVRecall())
+	errorPatterns := result.TopErrors(*top)
+	if len(errorPatterns) > 0 {
+		fmt.Println("\ntop errors (gold => predicted):")
+		for _, p := range errorPatterns {
+			fmt.Printf("%6d  %s => %s\n", p.Count, p.Gold, p.Predicted)
+		}
+	}
+
+	if *report != "" {
+		r := result.Report(*top)
+		r.Options = opt
+		data, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(*report, append(data, '\n'), 0644)
+	}
+	return nil
+}
@@ -2,15 +2,38 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"segment"
 	"segment/dict"
 )
 
+// 子命令，参数是命令名后面的命令行参数
+var commands = map[string]func(args []string) error{
+	"address":    parseAddress,
+	"build-idf":  buildIdf,
+	"eval":       evalSegment,
+	"segment":    segmentText,
+	"train":      trainDict,
+	"train-hmm":  trainHmm,
+	"train-name": trainName,
+	"train-pos":  trainPos,
+}
+
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	seg := segment.NewSegment()
 	err := seg.Init("./dicts")
 	if err != nil {
-		fmt.Println("%v", err)
+		fmt.Println(err)
 	}
 	ret := seg.DoSegment(`盘古分词 简介: 盘古分词 是由eaglet 开发的一款基于字典的中英文分词组件
 主要功能: 中英文分词，未登录词识别,多元歧义自动识别,全角字符识别能力
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"segment"
+	"segment/dict"
+	"segment/match"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 按 "Name=value,Name2" 的格式设置 MatchOptions，只写名字表示打开开关
+func parseMatchOptions(text string, options *match.MatchOptions) error {
+	v := reflect.ValueOf(options).Elem()
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) == 0 {
+			continue
+		}
+		name, value := item, "true"
+		if i := strings.Index(item, "="); i >= 0 {
+			name, value = strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		}
+		field := v.FieldByName(name)
+		if !field.IsValid() {
+			return fmt.Errorf("unknown match option: %s", name)
+		}
+		switch field.Kind() {
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("bad value of match option %s: %s", name, value)
+			}
+			field.SetBool(b)
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int:
+			// 词性用以 | 分割的标注符号表示，如 ExcludePos=u|w
+			n, err := strconv.ParseInt(value, 0, 0)
+			if err != nil && strings.HasSuffix(name, "Pos") {
+				var pos int
+				pos, err = dict.ParsePosTags(value)
+				n = int64(pos)
+			}
+			if err != nil {
+				return fmt.Errorf("bad value of match option %s: %s", name, value)
+			}
+			field.SetInt(n)
+		case reflect.Slice:
+			// 字符串列表以 | 分割，如 ExtraStopWords=的|了、StopWordLists=zh|punct
+			if field.Type().Elem().Kind() != reflect.String {
+				return fmt.Errorf("unknown match option: %s", name)
+			}
+			field.Set(reflect.ValueOf(strings.Split(value, "|")))
+		default:
+			return fmt.Errorf("unknown match option: %s", name)
+		}
+	}
+	if options.Matcher != "" && !match.HasMatcher(options.Matcher) {
+		return fmt.Errorf("unknown matcher: %s (available: %s)", options.Matcher, strings.Join(match.MatcherNames(), ", "))
+	}
+	return nil
+}
+
+// 检查选项中的停用词表是否都已经加载，分词时没有加载的表会被忽略，命令行中写错的名字应该报错
+func checkStopWordLists(seg *segment.Segment, options *match.MatchOptions) error {
+	names := seg.StopWordListNames()
+	for _, name := range options.StopWordLists {
+		i := sort.SearchStrings(names, name)
+		if i == len(names) || names[i] != name {
+			return fmt.Errorf("unknown stop word list: %s (available: %s)", name, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+/**
+ * func:  Chinese address parsing
+ *
+ * 把 广东省深圳市南山区科技园南区深南大道10000号腾讯大厦3楼 这样的地址分解成
+ * 省/市/区县/片区/道路/门牌号/楼宇/楼层/房间，每一部分都带有在原字符串中的位置。
+ */
+
+package address
+
+import (
+	"container/list"
+	"regexp"
+	"segment"
+	"segment/dict"
+	"segment/match"
+	"segment/utils"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 道路名称的后缀，长的排在前面
+var STREET_SUFFIXES = []string{"大道", "大街", "胡同", "公路", "路", "街", "道", "巷", "弄"}
+
+// 片区名称的后缀：街道、乡镇、村、园区、小区等
+var AREA_SUFFIXES = []string{"街道", "社区", "小区", "开发区", "园区", "新区", "镇", "乡", "村", "园", "区"}
+
+// 没有行政区划表时按后缀识别的行政区划，依次是省级、地级、县级
+var adminSuffixes = [][]string{
+	{"省", "自治区", "特别行政区"},
+	{"市", "自治州", "地区", "盟"},
+	{"区", "县", "旗"},
+}
+
+var (
+	numberRe = regexp.MustCompile(`^[0-9０-９一二三四五六七八九十百千零〇-]+号院?`)
+	floorRe  = regexp.MustCompile(`[0-9０-９一二三四五六七八九十-]+[楼层Ff]`)
+	roomRe   = regexp.MustCompile(`[0-9A-Za-z０-９-]+[室房]`)
+	digitsRe = regexp.MustCompile(`^[0-9A-Za-z０-９-]+`)
+)
+
+// 地址的一部分。Position 和 Length 按字符计算，是在原字符串中的位置；
+// 根据下级区划推断出来的上级区划在原字符串中没有出现，Position 为 -1
+type Component struct {
+	Text     string `json:"text"`
+	Name     string `json:"name,omitempty"` // 行政区划的全称，如 深圳 的全称是 深圳市
+	Position int    `json:"position"`
+	Length   int    `json:"length"`
+}
+
+type Address struct {
+	Text     string     `json:"text"`
+	Province *Component `json:"province,omitempty"` // 省级
+	City     *Component `json:"city,omitempty"`     // 地级
+	District *Component `json:"district,omitempty"` // 县级
+	Area     *Component `json:"area,omitempty"`     // 区县和道路之间的片区：街道、乡镇、园区等
+	Street   *Component `json:"street,omitempty"`   // 道路
+	Number   *Component `json:"number,omitempty"`   // 门牌号
+	Building *Component `json:"building,omitempty"` // 楼宇
+	Floor    *Component `json:"floor,omitempty"`
+	Room     *Component `json:"room,omitempty"`
+	Detail   *Component `json:"detail,omitempty"` // 剩下没有识别的部分
+}
+
+// 地址解析器：先用行政区划表匹配省市区县（全称和简称都可以，可以缺少某一级），
+// 再用分词结果找出片区和道路，最后按规则找出门牌号、楼宇、楼层和房间
+type Parser struct {
+	seg       *segment.Segment
+	divisions *Divisions
+	options   *match.MatchOptions
+}
+
+// divisions 可以为 nil，这时只按 省/市/区/县 等后缀识别行政区划
+func NewParser(seg *segment.Segment, divisions *Divisions) *Parser {
+	options := match.NewMatchOptions()
+	options.MultiDimensionality = false
+	options.FilterStopWords = false
+	options.IgnoreSpace = false
+	options.PlaceNameIdentify = true
+	return &Parser{seg: seg, divisions: divisions, options: options}
+}
+
+type token struct {
+	start int
+	end   int
+	text  string
+}
+
+func (p *Parser) Parse(text string) *Address {
+	addr := &Address{Text: text}
+	runes := utils.ToRunes(text)
+	pos := p.matchDivisions(addr, runes, skipSeparators(runes, 0))
+
+	tokens := p.tokens(runes, pos)
+	i := p.matchAdminSuffixes(addr, runes, tokens)
+	if i < len(tokens) {
+		pos = p.matchStreet(addr, runes, tokens[i:])
+	} else if len(tokens) > 0 {
+		pos = tokens[len(tokens)-1].end
+	}
+	p.matchDetail(addr, runes, pos)
+	return addr
+}
+
+// 从 pos 开始按行政区划表匹配，下级必须属于已经匹配到的上级，返回匹配结束的位置
+func (p *Parser) matchDivisions(addr *Address, runes []rune, pos int) int {
+	if p.divisions == nil {
+		return pos
+	}
+	var last *Division
+	for pos < len(runes) {
+		var found *Division
+		length := 0
+		for l := p.divisions.maxLength; l >= 2; l-- {
+			if pos+l > len(runes) {
+				continue
+			}
+			if found = chooseDivision(p.divisions.Lookup(string(runes[pos:(pos+l)])), last); found != nil {
+				length = l
+				break
+			}
+		}
+		if found == nil {
+			break
+		}
+		l := length
+		setDivision(addr, found, &Component{Text: string(runes[pos:(pos + l)]), Name: found.Name, Position: pos, Length: l})
+		last = found
+		pos = skipSeparators(runes, pos+l)
+	}
+
+	// 缺少的上级从区划表推断
+	if last != nil {
+		for d := last.Parent; d != nil; d = d.Parent {
+			if getDivision(addr, d.Level) == nil {
+				setDivision(addr, d, &Component{Name: d.Name, Position: -1})
+			}
+		}
+	}
+	return pos
+}
+
+// 同名的区划中选择级别比 last 低并且属于 last 的，没有 last 时选择级别最高的
+func chooseDivision(candidates []*Division, last *Division) *Division {
+	var result *Division
+	for _, d := range candidates {
+		if last != nil && (d.Level <= last.Level || !last.IsAncestorOf(d)) {
+			continue
+		}
+		if result == nil || d.Level < result.Level {
+			result = d
+		}
+	}
+	return result
+}
+
+func getDivision(addr *Address, level int) *Component {
+	switch level {
+	case LevelProvince:
+		return addr.Province
+	case LevelCity:
+		return addr.City
+	case LevelDistrict:
+		return addr.District
+	}
+	return nil
+}
+
+func setDivision(addr *Address, d *Division, c *Component) {
+	switch d.Level {
+	case LevelProvince:
+		addr.Province = c
+	case LevelCity:
+		addr.City = c
+	case LevelDistrict:
+		addr.District = c
+	}
+}
+
+// 对 pos 之后的文本分词，得到互不重叠、按位置排列的词，位置是在整个地址中的位置
+func (p *Parser) tokens(runes []rune, pos int) []token {
+	if pos >= len(runes) {
+		return nil
+	}
+	words := p.seg.DoSegmentWithOption(string(runes[pos:]), p.options)
+	return toTokens(words, runes, pos)
+}
+
+func toTokens(words *list.List, runes []rune, offset int) []token {
+	tokens := []token{}
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		length := wi.OriginalLength
+		start := offset + wi.Position
+		if length > 0 && start+length <= len(runes) {
+			tokens = append(tokens, token{start: start, end: start + length, text: string(runes[start:(start + length)])})
+		}
+	}
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].start < tokens[j].start
+	})
+	result := []token{}
+	end := offset
+	for _, t := range tokens {
+		if t.start >= end {
+			result = append(result, t)
+			end = t.end
+		}
+	}
+	return result
+}
+
+// 区划表中没有的省市区县按后缀识别，只识别开头连续的、级别依次降低的几个词，返回第一个没有识别的词。
+// 直辖市是省级，后面直接是县级的区
+func (p *Parser) matchAdminSuffixes(addr *Address, runes []rune, tokens []token) int {
+	level := 0
+	for l := LevelProvince; l <= LevelDistrict; l++ {
+		if getDivision(addr, l) != nil {
+			level = l
+		}
+	}
+	i := 0
+	for ; i < len(tokens) && level < LevelDistrict; i++ {
+		t := tokens[i]
+		found := 0
+		if level == 0 && IsMunicipality(t.text) {
+			found = LevelProvince
+		}
+		for l := level + 1; l <= LevelDistrict && found == 0; l++ {
+			if utils.RuneLen(t.text) > 1 && hasSuffix(t.text, adminSuffixes[l-1]) != "" {
+				found = l
+			}
+		}
+		if found == 0 {
+			break
+		}
+		setDivision(addr, &Division{Level: found}, &Component{Text: t.text, Position: t.start, Length: t.end - t.start})
+		level = found
+	}
+	return i
+}
+
+// 找出道路和它前面的片区，返回已经识别的部分结束的位置
+func (p *Parser) matchStreet(addr *Address, runes []rune, tokens []token) int {
+	start := tokens[0].start
+	for j, t := range tokens {
+		if isNumeric(t.text) {
+			break
+		}
+		suffix := hasSuffix(t.text, STREET_SUFFIXES)
+		if suffix == "" {
+			continue
+		}
+		// 只有后缀的词和前一个词合起来是道路名，如 人民/路
+		first := j
+		if suffix == t.text {
+			if j == 0 {
+				continue
+			}
+			first--
+		}
+		// 道路名被切成了单字，如 深/南大/道
+		for first > 0 && isFragment(tokens[first-1]) {
+			first--
+		}
+		streetStart := tokens[first].start
+		// 分词把片区后缀切到了道路名中，如 科技/园科苑路
+		for k := 1; k <= 2 && streetStart > start && t.end-streetStart-k >= 2; k++ {
+			if hasSuffix(string(runes[start:(streetStart+k)]), AREA_SUFFIXES) != "" {
+				streetStart += k
+				break
+			}
+		}
+		addr.Street = newComponent(runes, streetStart, t.end)
+		addr.Area = newComponent(runes, start, streetStart)
+		return t.end
+	}
+
+	// 没有道路时，片区到最后一个以片区后缀结尾的词为止
+	end := start
+	for _, t := range tokens {
+		if isNumeric(t.text) {
+			break
+		}
+		if hasSuffix(t.text, AREA_SUFFIXES) != "" {
+			end = t.end
+		}
+	}
+	addr.Area = newComponent(runes, start, end)
+	return end
+}
+
+// 门牌号、楼宇、楼层和房间
+func (p *Parser) matchDetail(addr *Address, runes []rune, pos int) {
+	pos = skipSeparators(runes, pos)
+	if pos >= len(runes) {
+		return
+	}
+	rest := string(runes[pos:])
+	if loc := numberRe.FindStringIndex(rest); loc != nil {
+		addr.Number = newComponent(runes, pos, pos+runeCount(rest, loc[1]))
+		pos = skipSeparators(runes, addr.Number.Position+addr.Number.Length)
+		rest = string(runes[pos:])
+	}
+
+	buildingEnd := len(runes)
+	if loc := floorRe.FindStringIndex(rest); loc != nil {
+		buildingEnd = pos + runeCount(rest, loc[0])
+		addr.Floor = newComponent(runes, buildingEnd, pos+runeCount(rest, loc[1]))
+	} else if loc := roomRe.FindStringIndex(rest); loc != nil {
+		buildingEnd = pos + runeCount(rest, loc[0])
+	}
+	addr.Building = newComponent(runes, pos, buildingEnd)
+	pos = skipSeparators(runes, buildingEnd)
+	if addr.Floor != nil {
+		pos = skipSeparators(runes, addr.Floor.Position+addr.Floor.Length)
+	}
+
+	// 楼层后面的数字是房间号，没有楼层时房间号要以 室/房 结尾
+	rest = string(runes[pos:])
+	if loc := roomRe.FindStringIndex(rest); loc != nil && loc[0] == 0 {
+		addr.Room = newComponent(runes, pos, pos+runeCount(rest, loc[1]))
+	} else if loc := digitsRe.FindStringIndex(rest); loc != nil && addr.Floor != nil {
+		addr.Room = newComponent(runes, pos, pos+runeCount(rest, loc[1]))
+	}
+	if addr.Room != nil {
+		pos = skipSeparators(runes, addr.Room.Position+addr.Room.Length)
+	}
+	addr.Detail = newComponent(runes, pos, len(runes))
+}
+
+// runes[start:end] 去掉两边的空白和分隔符，为空时返回 nil
+func newComponent(runes []rune, start int, end int) *Component {
+	start = skipSeparators(runes, start)
+	for end > start && isSeparator(runes[end-1]) {
+		end--
+	}
+	if end <= start {
+		return nil
+	}
+	return &Component{Text: string(runes[start:end]), Position: start, Length: end - start}
+}
+
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune(",，、;；", r)
+}
+
+func skipSeparators(runes []rune, pos int) int {
+	for pos < len(runes) && isSeparator(runes[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func hasSuffix(word string, suffixes []string) string {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// 不是片区和数字的单字
+func isFragment(t token) bool {
+	return t.end-t.start == 1 && !isNumeric(t.text) && hasSuffix(t.text, AREA_SUFFIXES) == ""
+}
+
+func isNumeric(word string) bool {
+	r := utils.FirstRune(word)
+	return (r >= '0' && r <= '9') || (r >= '０' && r <= '９')
+}
+
+// s 的前 n 个字节中的字符数
+func runeCount(s string, n int) int {
+	return utf8.RuneCountInString(s[:n])
+}
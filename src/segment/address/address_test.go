@@ -0,0 +1,67 @@
+package address
+
+import (
+	"segment"
+	"strings"
+	"testing"
+)
+
+const testDictPath = "../../../bin/dicts"
+
+var testSegment *segment.Segment
+
+func loadTestSegment(t *testing.T) *segment.Segment {
+	if testSegment == nil {
+		s := segment.NewSegment()
+		if err := s.Init(testDictPath); err != nil {
+			t.Skip("dictionary not available: ", err)
+		}
+		testSegment = s
+	}
+	return testSegment
+}
+
+func componentText(c *Component) string {
+	if c == nil {
+		return ""
+	}
+	if c.Position < 0 {
+		return "(" + c.Name + ")"
+	}
+	return c.Text
+}
+
+// expected 依次是 省/市/区县/片区/道路/门牌号/楼宇/楼层/房间/剩下的部分
+func checkAddress(t *testing.T, parser *Parser, text string, expected []string) {
+	addr := parser.Parse(text)
+	got := []string{componentText(addr.Province), componentText(addr.City), componentText(addr.District),
+		componentText(addr.Area), componentText(addr.Street), componentText(addr.Number),
+		componentText(addr.Building), componentText(addr.Floor), componentText(addr.Room), componentText(addr.Detail)}
+	if strings.Join(got, "|") != strings.Join(expected, "|") {
+		t.Errorf("%s: got %q, want %q", text, got, expected)
+	}
+}
+
+// 推断出来的上级区划用括号括起来
+func TestParseWithDivisions(t *testing.T) {
+	divisions, err := LoadDivisions(testDictPath + "/" + DivisionFileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := NewParser(loadTestSegment(t), divisions)
+	checkAddress(t, parser, "广东省深圳市南山区科技园南区深南大道10000号腾讯大厦3楼",
+		[]string{"广东省", "深圳市", "南山区", "科技园南区", "深南大道", "10000号", "腾讯大厦", "3楼", "", ""})
+	checkAddress(t, parser, "广东省深圳市南山区科技园南区深南大道10000号腾讯大厦3楼301室",
+		[]string{"广东省", "深圳市", "南山区", "科技园南区", "深南大道", "10000号", "腾讯大厦", "3楼", "301室", ""})
+	checkAddress(t, parser, "深圳南山科技园", []string{"(广东省)", "深圳", "南山", "科技园", "", "", "", "", "", ""})
+	checkAddress(t, parser, "上海浦东新区张江镇", []string{"上海", "", "浦东新区", "张江镇", "", "", "", "", "", ""})
+	checkAddress(t, parser, "朝阳区建国路88号", []string{"(北京市)", "", "朝阳区", "", "建国路", "88号", "", "", "", ""})
+}
+
+// 没有行政区划表时按后缀识别，直辖市是省级。楼层后面的数字是房间号
+func TestParseWithoutDivisions(t *testing.T) {
+	parser := NewParser(loadTestSegment(t), nil)
+	checkAddress(t, parser, "北京市海淀区中关村大街27号中关村大厦5层502",
+		[]string{"北京市", "", "海淀区", "", "中关村大街", "27号", "中关村大厦", "5层", "502", ""})
+	checkAddress(t, parser, "湖北省武汉市黄陂区", []string{"湖北省", "武汉市", "黄陂区", "", "", "", "", "", "", ""})
+}
@@ -0,0 +1,123 @@
+package address
+
+import (
+	"segment/utils"
+	"strconv"
+	"strings"
+)
+
+const DivisionFileName = "AdminDivision.txt"
+
+// 行政区划的级别
+const (
+	LevelProvince = 1 // 省、自治区、直辖市
+	LevelCity     = 2 // 地级市、自治州、地区、盟
+	LevelDistrict = 3 // 市辖区、县、县级市、旗
+)
+
+// 行政区划名称的后缀，去掉后缀后至少两个字时作为简称，长的排在前面
+var DIVISION_SUFFIXES = []string{
+	"维吾尔自治区", "壮族自治区", "回族自治区", "特别行政区", "自治区", "自治州", "自治县",
+	"新区", "地区", "省", "市", "区", "县", "盟", "旗",
+}
+
+// 直辖市，是省级区划
+var MUNICIPALITIES = []string{"北京市", "天津市", "上海市", "重庆市"}
+
+// name 是否是直辖市的全称或简称
+func IsMunicipality(name string) bool {
+	for _, m := range MUNICIPALITIES {
+		if name == m || name == ShortName(m) {
+			return true
+		}
+	}
+	return false
+}
+
+type Division struct {
+	Name   string
+	Level  int
+	Parent *Division
+}
+
+// 是否是 d 本身或者 d 的上级
+func (d *Division) IsAncestorOf(child *Division) bool {
+	for c := child; c != nil; c = c.Parent {
+		if c == d {
+			return true
+		}
+	}
+	return false
+}
+
+// 行政区划表，全称和简称都可以查到
+type Divisions struct {
+	names     map[string][]*Division
+	maxLength int
+}
+
+func NewDivisions() *Divisions {
+	return &Divisions{names: make(map[string][]*Division)}
+}
+
+// 行政区划表每行的格式为 名称|级别|上级名称，级别是 1（省级）、2（地级）或 3（县级），
+// 上级必须出现在下级的前面，省级的上级为空。同名的区划用上级区分
+func LoadDivisions(file string) (*Divisions, error) {
+	d := NewDivisions()
+	err := utils.EachLine(file, func(line string) {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			return
+		}
+		fields := strings.Split(line, "|")
+		if len(fields) < 2 {
+			return
+		}
+		level, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return
+		}
+		var parent *Division
+		if len(fields) > 2 && fields[2] != "" {
+			for _, p := range d.names[fields[2]] {
+				if p.Name == fields[2] && p.Level < level {
+					parent = p
+				}
+			}
+		}
+		d.Add(&Division{Name: fields[0], Level: level, Parent: parent})
+	})
+	return d, err
+}
+
+func (d *Divisions) Add(division *Division) {
+	d.addName(division.Name, division)
+	if short := ShortName(division.Name); short != "" {
+		d.addName(short, division)
+	}
+}
+
+func (d *Divisions) addName(name string, division *Division) {
+	d.names[name] = append(d.names[name], division)
+	if l := utils.RuneLen(name); l > d.maxLength {
+		d.maxLength = l
+	}
+}
+
+// 去掉后缀的简称，如 广东省 的简称是 广东，没有简称时返回空串
+func ShortName(name string) string {
+	for _, suffix := range DIVISION_SUFFIXES {
+		if strings.HasSuffix(name, suffix) {
+			if short := strings.TrimSuffix(name, suffix); utils.RuneLen(short) >= 2 {
+				return short
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// 名称对应的区划，全称和简称都可以
+func (d *Divisions) Lookup(name string) []*Division {
+	return d.names[name]
+}
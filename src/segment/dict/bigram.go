@@ -0,0 +1,53 @@
+package dict
+
+import (
+	"segment/utils"
+	"strconv"
+	"strings"
+)
+
+const BigramFileName = "Bigram.txt"
+
+// 词的二元转移次数，文件中一行一项：前一个词、后一个词、次数，以 Tab 或空格分割
+type Bigram struct {
+	bigramDict map[string](map[string]float64)
+}
+
+func NewBigram() *Bigram {
+	b := &Bigram{}
+	b.bigramDict = make(map[string](map[string]float64))
+	return b
+}
+
+// 二元转移表是可选的，不存在时不做处理
+func (b *Bigram) Load(dictPath string) (err error) {
+	err = utils.EachLineIfExist(dictPath+"/"+BigramFileName, func(line string) {
+		words := strings.Fields(line)
+		if len(words) == 3 {
+			count, e := strconv.ParseFloat(words[2], 64)
+			if e == nil {
+				b.Add(words[0], words[1], count)
+			}
+		}
+	})
+	return
+}
+
+func (b *Bigram) Add(prev string, word string, count float64) {
+	key := strings.ToLower(prev)
+	if _, ok := b.bigramDict[key]; !ok {
+		b.bigramDict[key] = make(map[string]float64)
+	}
+	b.bigramDict[key][strings.ToLower(word)] += count
+}
+
+func (b *Bigram) Count(prev string, word string) float64 {
+	if l, ok := b.bigramDict[strings.ToLower(prev)]; ok {
+		return l[strings.ToLower(word)]
+	}
+	return 0
+}
+
+func (b *Bigram) Len() int {
+	return len(b.bigramDict)
+}
@@ -1,15 +1,19 @@
 package dict
 
 import (
+	"os"
 	"segment/utils"
+	"strings"
 )
 
 const (
 	chsSingleNameFileName  = "ChsSingleName.txt"
 	chsDoubleName1FileName = "ChsDoubleName1.txt"
 	chsDoubleName2FileName = "ChsDoubleName2.txt"
+	chsFamilyNameFileName  = "ChsFamilyName.txt"
 )
 
+// 词典目录中没有 ChsFamilyName.txt 时使用的姓氏表
 var FAMILY_NAMES = []string{
 	//有明显歧异的姓氏
 	"王", "张", "黄", "周", "徐",
@@ -47,7 +51,7 @@ var FAMILY_NAMES = []string{
 	"闵", "贾", "娄", "颜",
 	"郭", "邱", "骆", "蔡",
 	"樊", "凌", "霍", "虞",
-	"柯", "昝", "卢", "柯",
+	"柯", "昝", "卢",
 	"缪", "宗", "丁", "贲",
 	"邓", "郁", "杭", "洪",
 	"崔", "龚", "嵇", "邢",
@@ -61,7 +65,7 @@ var FAMILY_NAMES = []string{
 	"郜", "黎", "蓟", "溥",
 	"蒲", "邰", "鄂", "咸",
 	"卓", "蔺", "屠", "乔",
-	"郁", "胥", "苍", "莘",
+	"胥", "苍", "莘",
 	"翟", "谭", "贡", "劳",
 	"冉", "郦", "雍", "璩",
 	"桑", "桂", "濮", "扈",
@@ -89,6 +93,10 @@ var FAMILY_NAMES = []string{
 	"司徒", "司空", "万俟"}
 
 type ChsName struct {
+	Threshold     float64 // 人名得分的阈值，得分低于阈值的人名不作为候选词
+	ContextWeight float64 // 有上下文线索时得分向 1 靠近的比例
+
+	model           *NameModel
 	familyNameDict  map[rune]([]rune)
 	singleNameDict  map[rune]rune
 	doubleName1Dict map[rune]rune
@@ -96,40 +104,81 @@ type ChsName struct {
 }
 
 func NewChsName() *ChsName {
-	c := &ChsName{}
+	c := &ChsName{ContextWeight: 0.5}
 	c.familyNameDict = make(map[rune]([]rune))
 	c.singleNameDict = make(map[rune]rune)
 	c.doubleName1Dict = make(map[rune]rune)
 	c.doubleName2Dict = make(map[rune]rune)
 	for _, name := range FAMILY_NAMES {
-		runes := utils.ToRunes(name)
-		if len(runes) == 1 {
-			if _, ok := c.familyNameDict[runes[0]]; !ok {
-				c.familyNameDict[runes[0]] = nil
-			}
-		} else {
-			if v, ok := c.familyNameDict[runes[0]]; ok {
-				if v == nil {
-					c.familyNameDict[runes[0]] = []rune{0}
-				}
-				c.familyNameDict[runes[0]] = append(c.familyNameDict[runes[0]], runes[1])
-			} else {
-				c.familyNameDict[runes[0]] = []rune{runes[1]}
-			}
-		}
+		c.AddFamilyName(name)
 	}
 	return c
 }
 
+// 加入一个单姓或者复姓，familyNameDict 中单姓对应 nil，
+// 同时是单姓和复姓首字的字对应的列表中有一个 0
+func (c *ChsName) AddFamilyName(name string) {
+	runes := utils.ToRunes(name)
+	switch len(runes) {
+	case 1:
+		v, ok := c.familyNameDict[runes[0]]
+		if !ok {
+			c.familyNameDict[runes[0]] = nil
+		} else if v != nil && !containsRune(v, 0) {
+			c.familyNameDict[runes[0]] = append(v, 0)
+		}
+	case 2:
+		v, ok := c.familyNameDict[runes[0]]
+		if containsRune(v, runes[1]) {
+			return
+		}
+		if ok && v == nil {
+			v = []rune{0}
+		}
+		c.familyNameDict[runes[0]] = append(v, runes[1])
+	}
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, c := range runes {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *ChsName) Load(dictPath string) (err error) {
 	if err = c.loadNameDict(dictPath+"/"+chsSingleNameFileName, c.singleNameDict); err == nil {
 		if err = c.loadNameDict(dictPath+"/"+chsDoubleName1FileName, c.doubleName1Dict); err == nil {
 			err = c.loadNameDict(dictPath+"/"+chsDoubleName2FileName, c.doubleName2Dict)
 		}
 	}
+	if err == nil {
+		err = c.LoadFamilyNames(dictPath)
+	}
+	if err == nil {
+		c.model, err = loadNameModel(dictPath + "/" + ChsNameModelFileName)
+	}
 	return
 }
 
+// 姓氏表 ChsFamilyName.txt 一行一个单姓或复姓，# 开头的行是注释。
+// 文件存在时代替内置的 FAMILY_NAMES，文件是可选的
+func (c *ChsName) LoadFamilyNames(dictPath string) error {
+	file := dictPath + "/" + chsFamilyNameFileName
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil
+	}
+	c.familyNameDict = make(map[rune]([]rune))
+	return utils.EachLine(file, func(line string) {
+		name := strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if len(name) > 0 && !strings.HasPrefix(name, "#") {
+			c.AddFamilyName(name)
+		}
+	})
+}
+
 func (c *ChsName) loadNameDict(filePath string, dict map[rune]rune) (err error) {
 	err = utils.EachLine(filePath, func(line string) {
 		if len(line) > 0 {
@@ -0,0 +1,224 @@
+package dict
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"segment/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const ChsNameModelFileName = "ChsNameModel.txt"
+
+// 字在人名中的位置
+const (
+	nameRoleSurname = iota // 姓，复姓整体作为一个键
+	nameRoleSingle         // 单名
+	nameRoleGiven1         // 双名的第一个字
+	nameRoleGiven2         // 双名的第二个字
+	nameRoleCount
+)
+
+// 人名上下文线索：前面的职务、称呼，后面的 说/表示/先生/同志 等
+var NAME_LEFT_CUES = []string{
+	"总统", "主席", "总理", "部长", "省长", "市长", "县长", "局长", "书记", "经理", "董事长", "主任",
+	"教授", "记者", "演员", "导演", "作家", "队员", "老师", "同学", "委员", "代表", "医生", "律师",
+}
+
+var NAME_RIGHT_CUES = []string{
+	"说", "表示", "指出", "认为", "强调", "介绍", "告诉", "称", "先生", "女士", "同志", "教授", "老师",
+}
+
+// 人名中每个字的位置概率：字出现在某个位置的次数除以字在语料中出现的总次数
+type NameModel struct {
+	counts map[string]*[nameRoleCount + 1]float64 // 各位置的次数，最后一项是总次数
+}
+
+func newNameModel() *NameModel {
+	return &NameModel{counts: make(map[string]*[nameRoleCount + 1]float64)}
+}
+
+func (m *NameModel) add(key string, role int, count float64) {
+	c, ok := m.counts[key]
+	if !ok {
+		c = &[nameRoleCount + 1]float64{}
+		m.counts[key] = c
+	}
+	if role < 0 {
+		c[nameRoleCount] += count
+	} else {
+		c[role] += count
+	}
+}
+
+// key 出现在 role 位置的概率，加一平滑
+func (m *NameModel) prob(key string, role int) float64 {
+	c, ok := m.counts[key]
+	if !ok {
+		return 0
+	}
+	return (c[role] + 1) / (math.Max(c[nameRoleCount], c[role]) + 2)
+}
+
+// 从人民日报格式的语料训练人名模型，标注为 nr 的词是人名，相连的 nr（如 江/nr 泽民/nr）合成一个人名。
+// chsName 用来把人名切分成姓和名
+func TrainNameModel(corpusFile string, chsName *ChsName) (*NameModel, error) {
+	m := newNameModel()
+	err := EachCorpusLine(corpusFile, func(words []string, tags []string) {
+		name := ""
+		for i, word := range words {
+			for _, r := range word {
+				m.add(string(r), -1, 1)
+			}
+			if ParsePosTag(tags[i])&POS_A_NR != 0 {
+				name += word
+				if i+1 < len(words) && ParsePosTag(tags[i+1])&POS_A_NR != 0 {
+					continue
+				}
+			}
+			if name != "" {
+				m.addName(chsName, name)
+				name = ""
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *NameModel) addName(chsName *ChsName, name string) {
+	surname, given := chsName.splitName(utils.ToRunes(name))
+	if surname == nil {
+		return
+	}
+	if len(surname) > 1 {
+		m.add(string(surname), -1, 1)
+	}
+	m.add(string(surname), nameRoleSurname, 1)
+	if len(given) == 1 {
+		m.add(string(given), nameRoleSingle, 1)
+	} else {
+		m.add(string(given[:1]), nameRoleGiven1, 1)
+		m.add(string(given[1:]), nameRoleGiven2, 1)
+	}
+}
+
+// 每行的格式为 字|总次数|姓|单名|双名首字|双名末字
+func (m *NameModel) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	keys := make([]string, 0, len(m.counts))
+	for key, c := range m.counts {
+		if c[nameRoleSurname]+c[nameRoleSingle]+c[nameRoleGiven1]+c[nameRoleGiven2] > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	w := bufio.NewWriter(f)
+	for _, key := range keys {
+		c := m.counts[key]
+		fmt.Fprintf(w, "%s|%g|%g|%g|%g|%g\n", key, c[nameRoleCount], c[nameRoleSurname], c[nameRoleSingle], c[nameRoleGiven1], c[nameRoleGiven2])
+	}
+	return w.Flush()
+}
+
+func loadNameModel(file string) (m *NameModel, err error) {
+	m = newNameModel()
+	found := false
+	err = utils.EachLineIfExist(file, func(line string) {
+		found = true
+		fields := strings.Split(strings.TrimSpace(strings.TrimPrefix(line, "\ufeff")), "|")
+		if len(fields) != nameRoleCount+2 {
+			return
+		}
+		if total, e := strconv.ParseFloat(fields[1], 64); e == nil {
+			m.add(fields[0], -1, total)
+		}
+		for role := 0; role < nameRoleCount; role++ {
+			if count, e := strconv.ParseFloat(fields[role+2], 64); e == nil {
+				m.add(fields[0], role, count)
+			}
+		}
+	})
+	if !found {
+		m = nil
+	}
+	return
+}
+
+// 把人名切分成姓和名，名是一个或两个字，不是人名时返回 nil
+func (c *ChsName) splitName(name []rune) (surname []rune, given []rune) {
+	for _, l := range c.familyNameLength(name, 0) {
+		if gl := len(name) - l; gl == 1 || gl == 2 {
+			return name[:l], name[l:]
+		}
+	}
+	return nil, nil
+}
+
+// 人名的得分，在 0 到 1 之间：没有模型时为 0.5，有模型时是每个字位置概率的几何平均；
+// 前面或者后面有上下文线索时，得分按 ContextWeight 向 1 靠近
+func (c *ChsName) Score(text []rune, start int, name string) float64 {
+	runes := utils.ToRunes(name)
+	score := 0.5
+	if c.model != nil {
+		surname, given := c.splitName(runes)
+		if surname == nil {
+			return 0
+		}
+		logSum := math.Log(c.model.prob(string(surname), nameRoleSurname))
+		if len(given) == 1 {
+			logSum += math.Log(c.model.prob(string(given), nameRoleSingle))
+		} else {
+			logSum += math.Log(c.model.prob(string(given[:1]), nameRoleGiven1))
+			logSum += math.Log(c.model.prob(string(given[1:]), nameRoleGiven2))
+		}
+		score = math.Exp(logSum / float64(1+len(given)))
+	}
+
+	if c.hasContextCue(text, start, start+len(runes)) {
+		score += (1 - score) * c.ContextWeight
+	}
+	return score
+}
+
+func (c *ChsName) hasContextCue(text []rune, start int, end int) bool {
+	for _, cue := range NAME_LEFT_CUES {
+		l := utils.RuneLen(cue)
+		if start >= l && string(text[(start-l):start]) == cue {
+			return true
+		}
+	}
+	for _, cue := range NAME_RIGHT_CUES {
+		l := utils.RuneLen(cue)
+		if end+l <= len(text) && string(text[end:(end+l)]) == cue {
+			return true
+		}
+	}
+	return false
+}
+
+// 识别从 start 开始的人名并打分，得分低于 Threshold 的人名不返回
+func (c *ChsName) MatchScored(text []rune, start int) []*WordAttr {
+	result := []*WordAttr{}
+	for _, name := range c.Match(text, start) {
+		score := c.Score(text, start, name)
+		if score < c.Threshold {
+			continue
+		}
+		wa := NewWordAttr(name, POS_A_NR, 0)
+		wa.Score = score
+		result = append(result, wa)
+	}
+	return result
+}
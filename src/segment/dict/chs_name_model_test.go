@@ -0,0 +1,122 @@
+package dict
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 人民日报格式的小语料：张三、李四、王小明 是人名，在 和 三 大多不出现在人名中
+var testNameCorpus = []string{
+	"张/nr 三/nr 在/p 北京/ns 工作/v 。/w",
+	"李/nr 四/nr 在/p 一月份/t 来到/v 上海/ns 。/w",
+	"记者/n 王/nr 小明/nr 说/v ，/w 他/r 在/p 家/n 。/w",
+	"张/nr 三/nr 和/c 李/nr 四/nr 在/p 会上/s 发言/v 。/w",
+	"三/m 个/q 人/n 在/p 学校/n 里/f 。/w",
+	"他/r 在/p 三月/t 回来/v 。/w",
+}
+
+func trainTestNameModel(t *testing.T) *NameModel {
+	file := filepath.Join(t.TempDir(), "corpus.txt")
+	if err := os.WriteFile(file, []byte(strings.Join(testNameCorpus, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := TrainNameModel(file, NewChsName())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+// 姓 张，单名 三，双名 三在 都在字表中，张三在一月份 中 张三 和 张三在 都是候选人名
+func newTestChsName(model *NameModel) *ChsName {
+	c := NewChsName()
+	c.singleNameDict['三'] = '三'
+	c.doubleName1Dict['三'] = '三'
+	c.doubleName2Dict['在'] = '在'
+	c.model = model
+	return c
+}
+
+func TestNameScoreWithoutModel(t *testing.T) {
+	c := newTestChsName(nil)
+	cases := []struct {
+		text     string
+		start    int
+		expected float64
+	}{
+		{"张三在一月份", 0, 0.5},
+		{"张三说", 0, 0.75},    // 后面有 说
+		{"记者张三来了", 2, 0.75}, // 前面有 记者
+	}
+	for _, tc := range cases {
+		if got := c.Score([]rune(tc.text), tc.start, "张三"); math.Abs(got-tc.expected) > 1e-9 {
+			t.Errorf("%s: got %g, want %g", tc.text, got, tc.expected)
+		}
+	}
+}
+
+func TestNameModel(t *testing.T) {
+	m := trainTestNameModel(t)
+	if p := m.prob("张", nameRoleSurname); p < 0.5 {
+		t.Errorf("张 as a surname: got %g", p)
+	}
+	if m.prob("在", nameRoleGiven2) >= m.prob("明", nameRoleGiven2) {
+		t.Errorf("在 should be less likely than 明 as the second given-name character")
+	}
+	if p := m.prob("不存在", nameRoleSurname); p != 0 {
+		t.Errorf("unknown key: got %g", p)
+	}
+
+	// 保存后再加载的模型打分相同
+	file := filepath.Join(t.TempDir(), ChsNameModelFileName)
+	if err := m.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadNameModel(file)
+	if err != nil || loaded == nil {
+		t.Fatalf("load model: %v", err)
+	}
+	text := []rune("王小明说")
+	if a, b := newTestChsName(m).Score(text, 0, "王小明"), newTestChsName(loaded).Score(text, 0, "王小明"); math.Abs(a-b) > 1e-9 {
+		t.Errorf("score changed after saving and loading: %g, %g", a, b)
+	}
+}
+
+// 张三在一月份：张三在 的 在 很少出现在人名中，得分低于阈值，只有 张三 作为候选词
+func TestMatchScoredThreshold(t *testing.T) {
+	c := newTestChsName(trainTestNameModel(t))
+	text := []rune("张三在一月份")
+
+	c.Threshold = 0
+	all := c.MatchScored(text, 0)
+	if len(all) != 2 {
+		t.Fatalf("threshold 0: got %d names, want 2", len(all))
+	}
+	scores := make(map[string]float64)
+	for _, wa := range all {
+		if wa.Pos != POS_A_NR || wa.Score <= 0 || wa.Score > 1 {
+			t.Errorf("%s: pos %x, score %g", wa.Word, wa.Pos, wa.Score)
+		}
+		scores[wa.Word] = wa.Score
+	}
+	if scores["张三"] <= scores["张三在"] {
+		t.Fatalf("张三 %g should score higher than 张三在 %g", scores["张三"], scores["张三在"])
+	}
+
+	c.Threshold = (scores["张三"] + scores["张三在"]) / 2
+	names := []string{}
+	for _, wa := range c.MatchScored(text, 0) {
+		names = append(names, wa.Word)
+	}
+	if strings.Join(names, "/") != "张三" {
+		t.Errorf("threshold %g: got %v, want [张三]", c.Threshold, names)
+	}
+
+	// 上下文线索提高得分
+	if c.Score([]rune("张三说"), 0, "张三") <= scores["张三"] {
+		t.Errorf("a context cue should raise the score")
+	}
+}
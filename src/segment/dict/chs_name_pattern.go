@@ -0,0 +1,136 @@
+package dict
+
+import (
+	"segment/utils"
+)
+
+// 人名的扩展形式，每种形式单独开关
+const (
+	NamePatternFourChars = 1 << iota // 复姓加双名，如 欧阳娜娜、上官婉儿；两个单姓加双名，如 陈方安生
+	NamePatternPrefix                // 老/小 加姓，阿 加姓或名，如 老王、小李、阿强
+	NamePatternTitle                 // 姓加称谓，如 王先生、李总、欧阳老师
+)
+
+var NAME_PREFIXES = []string{"老", "小", "阿"}
+
+// 称谓，长的排在前面。单字的称谓 总/工/老/哥/姐 后面的字和称谓组成词时不作为称谓，
+// 如 钱总是 中的 总是、周工作日 中的 工作
+var NAME_TITLES = []string{
+	"董事长", "总经理", "先生", "女士", "小姐", "太太", "老师", "教授", "博士", "医生", "大夫",
+	"师傅", "老板", "经理", "主任", "书记", "局长", "部长", "院长", "校长", "处长", "科长",
+	"厂长", "县长", "市长", "省长", "同学", "阿姨", "总", "工", "老", "哥", "姐",
+}
+
+// 按开关识别人名的扩展形式，作为 POS_A_NR 的候选词，和 ChsName.Match 识别的人名一起由匹配器选择
+type ChsNamePattern struct {
+	name     *ChsName
+	wordDict *WordDictionary
+	patterns int
+	titles   map[string]bool
+	maxTitle int
+}
+
+// patterns 是 NamePatternFourChars 等形式的组合。称谓表在这里生成，
+// 调用者应该在 patterns 改变时才重新创建
+func (c *ChsName) Patterns(wdict *WordDictionary, patterns int) *ChsNamePattern {
+	p := &ChsNamePattern{name: c, wordDict: wdict, patterns: patterns, titles: make(map[string]bool)}
+	for _, title := range NAME_TITLES {
+		p.titles[title] = true
+		if l := utils.RuneLen(title); l > p.maxTitle {
+			p.maxTitle = l
+		}
+	}
+	return p
+}
+
+// 启用的人名形式
+func (p *ChsNamePattern) Patterns() int {
+	return p.patterns
+}
+
+// 从 start 开始的姓可能的长度，复姓在前，首字和复姓首字相同的单姓也返回 1，不是姓时返回 nil
+func (c *ChsName) familyNameLength(text []rune, start int) (lengths []int) {
+	if start >= len(text) {
+		return nil
+	}
+	f2List, ok := c.familyNameDict[text[start]]
+	if !ok {
+		return nil
+	}
+	if f2List == nil {
+		return []int{1}
+	}
+	if start+1 < len(text) && containsRune(f2List, text[start+1]) {
+		lengths = append(lengths, 2)
+	}
+	if containsRune(f2List, 0) {
+		lengths = append(lengths, 1)
+	}
+	return lengths
+}
+
+func (p *ChsNamePattern) Match(text []rune, index int) []*WordAttr {
+	found := make(map[int]float64)
+	c := p.name
+
+	if p.patterns&NamePatternFourChars != 0 && index+4 <= len(text) {
+		lengths := c.familyNameLength(text, index)
+		if len(lengths) > 0 && lengths[0] == 2 && p.isGivenName(text, index+2) {
+			found[4] = 0
+		}
+		if p.isSingleFamilyName(text, index) && p.isSingleFamilyName(text, index+1) {
+			_, ok1 := c.doubleName1Dict[text[index+2]]
+			_, ok2 := c.doubleName2Dict[text[index+3]]
+			if ok1 && ok2 {
+				found[4] = 0
+			}
+		}
+	}
+
+	if p.patterns&NamePatternPrefix != 0 && index+1 < len(text) {
+		switch string(text[index : index+1]) {
+		case "老", "小":
+			if p.isSingleFamilyName(text, index+1) {
+				found[2] = 0
+			}
+		case "阿":
+			if _, ok := c.singleNameDict[text[index+1]]; ok || p.isSingleFamilyName(text, index+1) {
+				found[2] = 0
+			}
+		}
+	}
+
+	if p.patterns&NamePatternTitle != 0 {
+		for _, fl := range c.familyNameLength(text, index) {
+			for l := 1; l <= p.maxTitle && index+fl+l <= len(text); l++ {
+				if p.titles[string(text[(index+fl):(index+fl+l)])] && (l > 1 || !breaksWord(p.wordDict, text, index+fl+l)) {
+					found[fl+l] = 0
+				}
+			}
+		}
+	}
+
+	return newCandidates(p.wordDict, text, index, found, nil, POS_A_NR)
+}
+
+// 复姓后面从 pos 开始的两个字的名：是词典中的词时必须是人名，否则两个字要分别在双名首字、
+// 双名尾字或单名的字表中，不能含有动词、方位词、量词等单字，也不能截断后面的词
+func (p *ChsNamePattern) isGivenName(text []rune, pos int) bool {
+	c := p.name
+	given := text[pos:(pos + 2)]
+	if wa := p.wordDict.GetWordAttr(given); wa != nil {
+		return wa.Pos&POS_A_NR != 0
+	}
+	_, first1 := c.doubleName1Dict[given[0]]
+	_, first2 := c.singleNameDict[given[0]]
+	_, last1 := c.doubleName2Dict[given[1]]
+	_, last2 := c.singleNameDict[given[1]]
+	return (first1 || first2) && (last1 || last2) &&
+		isGuessFragment(p.wordDict, given) && !breaksWord(p.wordDict, text, pos+2)
+}
+
+// text[pos] 是单姓，并且和后一个字不组成复姓
+func (p *ChsNamePattern) isSingleFamilyName(text []rune, pos int) bool {
+	lengths := p.name.familyNameLength(text, pos)
+	return len(lengths) == 1 && lengths[0] == 1
+}
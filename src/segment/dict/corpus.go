@@ -0,0 +1,45 @@
+package dict
+
+import (
+	"regexp"
+	"segment/utils"
+	"strings"
+)
+
+// 人民日报语料每行开头的编号，如 19980101-01-001-001/m
+var corpusLineId = regexp.MustCompile(`^\d{8}-\d{2}-\d{3}-\d{3}$`)
+
+// 解析分好词的语料中的一行，词之间以空白分割，词后面可以带 /词性，
+// 也支持人民日报语料中以 [] 括起来的复合词，如 [中国/ns 政府/n]nt，只取里面的词。
+// 没有标注词性的词，对应的词性为空字符串
+func ParseCorpusLine(line string) (words []string, tags []string) {
+	for _, token := range strings.Fields(line) {
+		if strings.HasPrefix(token, "[") && len(token) > 1 {
+			token = token[1:]
+		}
+		if i := strings.LastIndex(token, "]"); i > 0 && strings.Contains(token[:i], "/") {
+			token = token[:i]
+		}
+
+		word, tag := token, ""
+		if i := strings.LastIndex(token, "/"); i > 0 {
+			word, tag = token[:i], token[i+1:]
+		}
+		if corpusLineId.MatchString(word) {
+			continue
+		}
+		words = append(words, word)
+		tags = append(tags, tag)
+	}
+	return
+}
+
+// 逐行读取分好词的语料，跳过空行
+func EachCorpusLine(file string, handle func(words []string, tags []string)) error {
+	return utils.EachLine(file, func(line string) {
+		words, tags := ParseCorpusLine(line)
+		if len(words) > 0 {
+			handle(words, tags)
+		}
+	})
+}
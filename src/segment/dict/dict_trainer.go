@@ -0,0 +1,126 @@
+package dict
+
+import (
+	"bufio"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 词性在一个词所有出现中所占比例不低于这个值时才计入这个词的词性
+const DictTrainerPosThreshold float64 = 0.01
+
+// 从分好词的语料统计词频和词性分布，生成 WordDictionary.Load 可以读取的 Dict.txt
+type DictTrainer struct {
+	Scale   float64 // 语料词频乘以这个系数后再和基础词典的词频相加
+	MinFreq float64 // 语料中出现次数小于这个值的新词不写入词典
+
+	count    map[string]float64
+	posCount map[string](map[int]float64)
+	base     map[string](*WordAttr)
+	order    []string // 基础词典中词的顺序
+}
+
+func NewDictTrainer() *DictTrainer {
+	t := &DictTrainer{Scale: 1, MinFreq: 1}
+	t.count = make(map[string]float64)
+	t.posCount = make(map[string](map[int]float64))
+	t.base = make(map[string](*WordAttr))
+	return t
+}
+
+// 加载要合并的基础词典，格式和 Dict.txt 相同
+func (t *DictTrainer) LoadBase(fileName string) error {
+	waList, err := NewWordDictionary().loadFromTextFile(fileName)
+	if err != nil {
+		return err
+	}
+	for e := waList.Front(); e != nil; e = e.Next() {
+		wa := e.Value.(*WordAttr)
+		wa.Word = strings.TrimPrefix(wa.Word, "\ufeff")
+		if len(wa.Word) == 0 {
+			continue
+		}
+		if _, ok := t.base[wa.Word]; !ok {
+			t.order = append(t.order, wa.Word)
+		}
+		t.base[wa.Word] = wa
+	}
+	return nil
+}
+
+// 增加一个分好词的句子，tags 和 words 一一对应，可以为空字符串，标点符号不计入词典
+func (t *DictTrainer) Add(words []string, tags []string) {
+	for i, word := range words {
+		tag := ""
+		if i < len(tags) {
+			tag = tags[i]
+		}
+		pos := ParsePosTag(tag)
+		if len(word) == 0 || pos == POS_D_W {
+			continue
+		}
+		t.count[word]++
+		if pos != POS_UNK {
+			if _, ok := t.posCount[word]; !ok {
+				t.posCount[word] = make(map[int]float64)
+			}
+			t.posCount[word][pos]++
+		}
+	}
+}
+
+// 增加一个语料文件，格式见 ParseCorpusLine
+func (t *DictTrainer) AddCorpus(fileName string) error {
+	return EachCorpusLine(fileName, t.Add)
+}
+
+// 统计结果和基础词典合并后的词条，基础词典中的词保持原来的顺序，新词按词频从高到低排在后面
+func (t *DictTrainer) WordAttrs() []*WordAttr {
+	result := make([]*WordAttr, 0, len(t.order)+len(t.count))
+	for _, word := range t.order {
+		wa := t.base[word]
+		result = append(result, NewWordAttr(word, wa.Pos|t.pos(word), wa.Frequency+t.count[word]*t.Scale))
+	}
+
+	newWords := []*WordAttr{}
+	for word, c := range t.count {
+		if _, ok := t.base[word]; ok || c < t.MinFreq {
+			continue
+		}
+		newWords = append(newWords, NewWordAttr(word, t.pos(word), c*t.Scale))
+	}
+	sort.Slice(newWords, func(i, j int) bool {
+		if newWords[i].Frequency != newWords[j].Frequency {
+			return newWords[i].Frequency > newWords[j].Frequency
+		}
+		return newWords[i].Word < newWords[j].Word
+	})
+	return append(result, newWords...)
+}
+
+func (t *DictTrainer) pos(word string) int {
+	pos := POS_UNK
+	for p, c := range t.posCount[word] {
+		if c >= t.count[word]*DictTrainerPosThreshold {
+			pos |= p
+		}
+	}
+	return pos
+}
+
+// 按 Dict.txt 的格式保存，一行一个词：词|0x词性|词频
+func (t *DictTrainer) Save(fileName string) error {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for _, wa := range t.WordAttrs() {
+		w.WriteString(wa.Word + "|0x" + strconv.FormatInt(int64(wa.Pos), 16) + "|" + strconv.FormatFloat(wa.Frequency, 'f', -1, 64) + "\n")
+	}
+	return w.Flush()
+}
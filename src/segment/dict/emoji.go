@@ -0,0 +1,108 @@
+package dict
+
+import (
+	"segment/utils"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	EmojiFileName    = "Emoji.txt"
+	EmoticonFileName = "Emoticon.txt"
+)
+
+type Emoji struct {
+	nameDict     map[string]string   // 表情符号 => 简短名称，文件中一行一个，以 Tab 分割
+	emoticonDict map[rune]([][]rune) // 多字符颜文字，按首字索引，长的在前
+}
+
+func NewEmoji() *Emoji {
+	e := &Emoji{}
+	e.nameDict = make(map[string]string)
+	e.emoticonDict = make(map[rune]([][]rune))
+	return e
+}
+
+// 两个文件都是可选的，不存在时不做处理
+func (e *Emoji) Load(dictPath string) (err error) {
+	err = utils.EachLineIfExist(dictPath+"/"+EmojiFileName, func(line string) {
+		words := strings.Split(line, "\t")
+		if len(words) == 2 {
+			e.nameDict[strings.TrimSpace(words[0])] = strings.TrimSpace(words[1])
+		}
+	})
+	if err == nil {
+		err = utils.EachLineIfExist(dictPath+"/"+EmoticonFileName, func(line string) {
+			e.AddEmoticon(line)
+		})
+	}
+	return
+}
+
+func (e *Emoji) AddEmoticon(emoticon string) {
+	runes := utils.ToRunes(strings.TrimSpace(emoticon))
+	if len(runes) < 2 {
+		return
+	}
+	l := e.emoticonDict[runes[0]]
+	for _, r := range l {
+		if string(r) == string(runes) {
+			return
+		}
+	}
+	l = append(l, runes)
+	sort.Sort(runesByLength(l))
+	e.emoticonDict[runes[0]] = l
+}
+
+// 返回 text[start:] 开头的最长颜文字长度，没有则返回 0
+// 颜文字首尾是字母或数字时，要求和相邻的字符之间有边界，避免把 XDR 切成 XD/R；
+// 后面紧跟 / 时多半是网址或路径，也不作为颜文字
+func (e *Emoji) MatchEmoticon(text []rune, start int) int {
+	l, ok := e.emoticonDict[text[start]]
+	if !ok {
+		return 0
+	}
+	for _, emoticon := range l {
+		end := start + len(emoticon)
+		if end > len(text) || string(text[start:end]) != string(emoticon) {
+			continue
+		}
+		if start > 0 && isLetterOrDigit(emoticon[0]) && isLetterOrDigit(text[start-1]) {
+			continue
+		}
+		if end < len(text) && isLetterOrDigit(emoticon[len(emoticon)-1]) && isLetterOrDigit(text[end]) {
+			continue
+		}
+		if end < len(text) && text[end] == '/' {
+			continue
+		}
+		return len(emoticon)
+	}
+	return 0
+}
+
+// 先精确查找，找不到时去掉变体选择符和肤色修饰符后再查找
+func (e *Emoji) GetName(emoji string) string {
+	if name, ok := e.nameDict[emoji]; ok {
+		return name
+	}
+	base := strings.Map(func(r rune) rune {
+		if (r >= 0xfe00 && r <= 0xfe0f) || (r >= 0x1f3fb && r <= 0x1f3ff) {
+			return -1
+		}
+		return r
+	}, emoji)
+	return e.nameDict[base]
+}
+
+func isLetterOrDigit(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type runesByLength []([]rune)
+
+func (s runesByLength) Len() int           { return len(s) }
+func (s runesByLength) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s runesByLength) Less(i, j int) bool { return len(s[i]) > len(s[j]) }
@@ -0,0 +1,163 @@
+package dict
+
+import (
+	"segment/utils"
+	"strings"
+	"unicode"
+)
+
+const foreignNameCharFileName = "ForeignNameChar.txt"
+
+// 音译外国人名常用的字
+const FOREIGN_NAME_CHARS = "阿埃艾爱安昂奥巴芭白拜班邦保鲍贝本比彼毕宾波伯博布查达戴黛丹道德登迪蒂丁东杜顿多厄恩尔法菲费芬丰弗夫福盖甘冈戈格根贡古圭哈海汉翰豪赫亨洪霍基吉加贾杰金卡凯坎康柯科克肯库夸奎拉莱兰朗劳勒雷蕾里利丽莉林琳隆卢鲁伦罗洛马玛迈麦曼梅蒙米密莫墨穆默姆纳娜奈内尼妮涅宁纽努诺欧帕潘佩皮珀普奇齐恰乔切钦琼丘萨塞赛桑瑟森莎舍施斯松苏索塔泰坦汤特提汀托瓦威韦维温沃乌西希锡谢辛逊休雅亚扬耶伊因尤约泽扎詹佐兹茨祖娅茜丝薇"
+
+// 外国人名中名和姓之间的分隔符
+const FOREIGN_NAME_SEPARATORS = "·•・‧"
+
+// 常见的日本姓氏，后面跟两个字的名
+var JAPANESE_FAMILY_NAMES = []string{
+	"山口", "田中", "铃木", "佐藤", "高桥", "渡边", "伊藤", "山本", "中村", "小林",
+	"加藤", "吉田", "山田", "佐佐木", "松本", "井上", "木村", "清水", "山崎", "森田",
+	"池田", "桥本", "石川", "前田", "藤田", "后藤", "冈田", "长谷川", "村上", "近藤",
+	"安倍", "福田", "宫崎", "川端", "村田", "黑泽", "野田", "菅", "岸田", "小泉",
+}
+
+// 外国人名识别：
+// 1. 由音译用字组成、用 · 分隔名和姓的人名，如 奥巴马、伊丽莎白·泰勒
+// 2. 日本姓氏加上两个字的名，如 山口百惠
+// 音译用字中有很多常用字，音译人名不能截断词典中的词，也不能完全由词典中不是专名的词组成，
+// 如 马拉松比赛 中的 马拉、马拉松比赛。识别出来的人名作为 POS_A_NR 的候选词由匹配器选择
+type ForeignName struct {
+	Frequency float64 // 识别出的外国人名的词频
+	MinLength int     // 音译人名最少的字数（不算分隔符）
+	MaxLength int     // 音译人名最多的字数（包括分隔符）
+
+	wordDict        *WordDictionary
+	chars           map[rune]bool
+	japaneseNames   map[string]bool
+	maxJapaneseName int
+}
+
+func NewForeignName(wdict *WordDictionary) *ForeignName {
+	f := &ForeignName{Frequency: 20, MinLength: 2, MaxLength: 16, wordDict: wdict}
+	f.chars = make(map[rune]bool)
+	for _, r := range FOREIGN_NAME_CHARS {
+		f.chars[r] = true
+	}
+	f.japaneseNames = make(map[string]bool)
+	for _, name := range JAPANESE_FAMILY_NAMES {
+		f.japaneseNames[name] = true
+		if l := utils.RuneLen(name); l > f.maxJapaneseName {
+			f.maxJapaneseName = l
+		}
+	}
+	return f
+}
+
+// 音译用字表每行若干个字，补充到 FOREIGN_NAME_CHARS 中，文件是可选的
+func (f *ForeignName) Load(dictPath string) error {
+	return utils.EachLineIfExist(dictPath+"/"+foreignNameCharFileName, func(line string) {
+		line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if strings.HasPrefix(line, "#") {
+			return
+		}
+		for _, r := range line {
+			if !unicode.IsSpace(r) {
+				f.chars[r] = true
+			}
+		}
+	})
+}
+
+func IsForeignNameSeparator(r rune) bool {
+	return strings.ContainsRune(FOREIGN_NAME_SEPARATORS, r)
+}
+
+// word 是否全部由音译用字组成
+func (f *ForeignName) IsTransliteration(word string) bool {
+	if len(word) == 0 {
+		return false
+	}
+	for _, r := range word {
+		if !f.chars[r] {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *ForeignName) Match(text []rune, index int) []*WordAttr {
+	found := make(map[int]float64)
+	if index >= len(text) || !f.chars[text[index]] {
+		f.matchJapanese(text, index, found)
+		return newCandidates(f.wordDict, text, index, found, nil, POS_A_NR)
+	}
+
+	// 音译用字连续出现，分隔符两边都必须是音译用字
+	count := 0
+	for i := index; i < len(text) && i-index < f.MaxLength; i++ {
+		if IsForeignNameSeparator(text[i]) {
+			if i+1 >= len(text) || !f.chars[text[i+1]] {
+				break
+			}
+			continue
+		}
+		if !f.chars[text[i]] {
+			break
+		}
+		count++
+		if count >= f.MinLength && f.isTransliteratedName(text, index, i+1) {
+			found[i+1-index] = f.Frequency
+		}
+	}
+	f.matchJapanese(text, index, found)
+	return newCandidates(f.wordDict, text, index, found, nil, POS_A_NR)
+}
+
+// text[start:end] 两端不截断词典中的词，并且用分隔符分开的各部分不全是由词典中不是专名的词组成的
+func (f *ForeignName) isTransliteratedName(text []rune, start int, end int) bool {
+	if breaksWord(f.wordDict, text, start) || breaksWord(f.wordDict, text, end) {
+		return false
+	}
+	begin := start
+	for i := start; i <= end; i++ {
+		if i < end && !IsForeignNameSeparator(text[i]) {
+			continue
+		}
+		if !f.isCommonPhrase(text[begin:i]) {
+			return true
+		}
+		begin = i + 1
+	}
+	return false
+}
+
+// 可以完整切分成词典中不是专名的多字词，如 马拉松/比赛
+func (f *ForeignName) isCommonPhrase(chunk []rune) bool {
+	covered := make([]bool, len(chunk)+1)
+	covered[0] = true
+	for i := 0; i < len(chunk); i++ {
+		if !covered[i] {
+			continue
+		}
+		for j := i + 2; j <= len(chunk); j++ {
+			if wa := f.wordDict.GetWordAttr(chunk[i:j]); wa != nil && wa.Pos&(POS_A_NR|POS_A_NS|POS_A_NZ) == 0 {
+				covered[j] = true
+			}
+		}
+	}
+	return covered[len(chunk)]
+}
+
+// 日本姓氏后面两个字的名不能是词典中的词，也不能含有动词、方位词、量词单字，见 isGuessFragment
+func (f *ForeignName) matchJapanese(text []rune, index int, found map[int]float64) {
+	for l := 1; l <= f.maxJapaneseName && index+l+2 <= len(text); l++ {
+		if !f.japaneseNames[string(text[index:(index+l)])] {
+			continue
+		}
+		given := text[(index + l):(index + l + 2)]
+		if isGuessFragment(f.wordDict, given) {
+			found[l+2] = f.Frequency
+		}
+	}
+}
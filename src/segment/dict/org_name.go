@@ -0,0 +1,143 @@
+package dict
+
+import (
+	"segment/utils"
+	"strings"
+)
+
+const orgSuffixFileName = "OrgSuffix.txt"
+
+// 机构名后缀
+var ORG_SUFFIXES = []string{
+	"股份有限公司", "有限责任公司", "有限公司", "分公司", "公司", "集团", "银行", "分行", "支行",
+	"大学", "学院", "中学", "小学", "医院", "研究院", "研究所", "委员会", "协会", "学会", "基金会",
+	"出版社", "电视台", "事务所", "办事处", "中心", "工厂", "厂", "局", "厅",
+}
+
+// 可以出现在机构名中间的词：名词、专名、区别词，如 中国人民银行 中的 人民，北京大学附属医院 中的 附属
+const orgComponentPos = POS_D_N | POS_A_NS | POS_A_NR | POS_A_NZ | POS_A_NT | POS_D_B
+
+// 机构名识别：以专名开头，中间是名词、专名或者机构名，以机构名后缀结尾，如
+// 华为/技术/有限公司，北京/大学/附属/医院，中国/人民/银行/上海/分行。
+// 开头的专名可以是词典中的地名、人名、其他专名，识别出来的地名，或者不在词典中的字号。
+// 识别出来的机构名作为 POS_A_NT 的候选词由匹配器选择，组成机构名的词仍然在词典中，索引模式下会作为子词输出。
+// 字号不能截断词典中的词，如 我们去医院 中的 们去；以字号开头的机构名是猜测的，带有 GuessPenalty
+type OrgName struct {
+	Frequency      float64 // 识别出的机构名的词频
+	MaxLength      int     // 机构名最多的字数
+	MaxBrandLength int     // 不在词典中的字号最多的字数
+
+	wordDict     *WordDictionary
+	places       Recognizer
+	suffixes     map[string]bool
+	maxSuffixLen int
+	nonBrand     map[rune]bool
+}
+
+// places 用来识别开头和中间的地名，可以为 nil
+func NewOrgName(wdict *WordDictionary, places Recognizer) *OrgName {
+	o := &OrgName{Frequency: 500, MaxLength: 16, MaxBrandLength: 3, wordDict: wdict, places: places}
+	o.suffixes = make(map[string]bool)
+	for _, suffix := range ORG_SUFFIXES {
+		o.addSuffix(suffix)
+	}
+	// 单字的地名后缀和机构名后缀不会出现在字号中，如 黄陂区 中的 陂区
+	o.nonBrand = make(map[rune]bool)
+	for _, suffixes := range [][]string{PLACE_SUFFIXES, ORG_SUFFIXES} {
+		for _, suffix := range suffixes {
+			if r := utils.ToRunes(suffix); len(r) == 1 {
+				o.nonBrand[r[0]] = true
+			}
+		}
+	}
+	return o
+}
+
+func (o *OrgName) addSuffix(suffix string) {
+	o.suffixes[suffix] = true
+	if l := utils.RuneLen(suffix); l > o.maxSuffixLen {
+		o.maxSuffixLen = l
+	}
+}
+
+// 后缀表一行一个后缀，补充到 ORG_SUFFIXES 中，文件是可选的
+func (o *OrgName) Load(dictPath string) error {
+	return utils.EachLineIfExist(dictPath+"/"+orgSuffixFileName, func(line string) {
+		suffix := strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+		if len(suffix) > 0 && !strings.HasPrefix(suffix, "#") {
+			o.addSuffix(suffix)
+		}
+	})
+}
+
+func (o *OrgName) Match(text []rune, index int) []*WordAttr {
+	end := len(text)
+	if index+o.MaxLength < end {
+		end = index + o.MaxLength
+	}
+
+	// reached[i] 表示 text[index:i] 可以作为机构名的开头部分，known[i] 表示不经过字号也可以到达
+	reached := make([]bool, end+1)
+	known := make([]bool, end+1)
+	for l := 2; l <= 4 && index+l <= end; l++ {
+		if wa := o.wordDict.GetWordAttr(text[index:(index + l)]); wa != nil && wa.Pos&(POS_A_NS|POS_A_NR|POS_A_NZ|POS_A_NT) != 0 {
+			reached[index+l], known[index+l] = true, true
+		}
+	}
+	if !breaksWord(o.wordDict, text, index) {
+		for l := 2; l <= o.MaxBrandLength && index+l <= end; l++ {
+			if o.isBrand(text[index:(index+l)]) && !breaksWord(o.wordDict, text, index+l) {
+				reached[index+l] = true
+			}
+		}
+	}
+	o.matchPlaces(text, index, end, reached, known, true)
+
+	found := make(map[int]float64)
+	guessed := make(map[int]bool)
+	for pos := index + 1; pos < end; pos++ {
+		if !reached[pos] {
+			continue
+		}
+		for l := 1; l <= o.maxSuffixLen && pos+l <= end; l++ {
+			if o.suffixes[string(text[pos:(pos+l)])] {
+				reached[pos+l] = true
+				known[pos+l] = known[pos+l] || known[pos]
+				found[pos+l-index] = o.Frequency
+				guessed[pos+l-index] = !known[pos+l]
+			}
+		}
+		for l := 2; l <= 4 && pos+l <= end; l++ {
+			if wa := o.wordDict.GetWordAttr(text[pos:(pos + l)]); wa != nil && wa.Pos&orgComponentPos != 0 {
+				reached[pos+l] = true
+				known[pos+l] = known[pos+l] || known[pos]
+			}
+		}
+		o.matchPlaces(text, pos, end, reached, known, known[pos])
+	}
+
+	return newCandidates(o.wordDict, text, index, found, guessed, POS_A_NT)
+}
+
+// 不在词典中的字号，不能含有地名、机构名的单字后缀，以及动词、方位词、量词等单字，如 有个小学 中的 有个
+func (o *OrgName) isBrand(fragment []rune) bool {
+	for _, r := range fragment {
+		if o.nonBrand[r] {
+			return false
+		}
+	}
+	return isGuessFragment(o.wordDict, fragment)
+}
+
+// 从 pos 开始的地名，isKnown 表示 pos 之前的部分是否不经过字号到达
+func (o *OrgName) matchPlaces(text []rune, pos int, end int, reached []bool, known []bool, isKnown bool) {
+	if o.places == nil {
+		return
+	}
+	for _, wa := range o.places.Match(text, pos) {
+		if l := utils.RuneLen(wa.Word); pos+l <= end {
+			reached[pos+l] = true
+			known[pos+l] = known[pos+l] || (isKnown && wa.Penalty == 0)
+		}
+	}
+}
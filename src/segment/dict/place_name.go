@@ -0,0 +1,162 @@
+package dict
+
+import (
+	"segment/utils"
+	"strconv"
+	"strings"
+)
+
+const (
+	placeNameFileName   = "PlaceName.txt"
+	placeSuffixFileName = "PlaceSuffix.txt"
+)
+
+// 地名后缀，长的后缀要排在前面
+var PLACE_SUFFIXES = []string{
+	"自治区", "自治州", "自治县", "开发区", "大街", "大道", "胡同",
+	"省", "市", "县", "区", "镇", "乡", "村", "路", "街", "巷", "州",
+}
+
+// 没有实际意义、只用来指代地名的词，如 某某村
+var placeHolders = map[string]bool{"某": true, "某某": true}
+
+// 地名识别：
+// 1. 地名表中的地名
+// 2. 地名表中的地名或者词典中的地名(ns)加上后缀，如 中关村大街
+// 3. 不在词典中的字或者名词加上后缀，如 黄陂区、某某村
+// 识别出来的地名作为 POS_A_NS 的候选词由匹配器选择，词频越高越容易被选中。
+// 猜测的地名不能截断词典中的词，如 在家里走路 中的 里走路、去逛街 中的 去逛，
+// 并且带有 GuessPenalty，只有比按词典切分的词数少时才会被选中
+type PlaceName struct {
+	GazetteerFrequency float64 // 地名表中的地名没有给出词频时的词频
+	SuffixFrequency    float64 // 已知地名加上后缀的词频
+	GuessFrequency     float64 // 猜测的地名的词频
+	MaxGuessLength     int     // 猜测的地名中后缀前面最多的字数
+
+	wordDict     *WordDictionary
+	places       map[string]float64
+	maxPlaceLen  int
+	suffixes     map[string]bool
+	maxSuffixLen int
+}
+
+func NewPlaceName(wdict *WordDictionary) *PlaceName {
+	p := &PlaceName{GazetteerFrequency: 1000, SuffixFrequency: 500, GuessFrequency: 10, MaxGuessLength: 3, wordDict: wdict}
+	p.places = make(map[string]float64)
+	p.suffixes = make(map[string]bool)
+	for _, suffix := range PLACE_SUFFIXES {
+		p.addSuffix(suffix)
+	}
+	return p
+}
+
+func (p *PlaceName) addSuffix(suffix string) {
+	p.suffixes[suffix] = true
+	if l := utils.RuneLen(suffix); l > p.maxSuffixLen {
+		p.maxSuffixLen = l
+	}
+}
+
+// 地名表一行一个地名，后面可以跟词频，以空白分割；后缀表一行一个后缀，补充到 PLACE_SUFFIXES 中。
+// 两个文件都是可选的
+func (p *PlaceName) Load(dictPath string) (err error) {
+	err = utils.EachLineIfExist(dictPath+"/"+placeNameFileName, func(line string) {
+		fields := strings.Fields(strings.TrimPrefix(line, "\ufeff"))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			return
+		}
+		freq := p.GazetteerFrequency
+		if len(fields) > 1 {
+			if f, e := strconv.ParseFloat(fields[1], 64); e == nil {
+				freq = f
+			}
+		}
+		p.AddPlace(fields[0], freq)
+	})
+	if err == nil {
+		err = utils.EachLineIfExist(dictPath+"/"+placeSuffixFileName, func(line string) {
+			suffix := strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+			if len(suffix) > 0 && !strings.HasPrefix(suffix, "#") {
+				p.addSuffix(suffix)
+			}
+		})
+	}
+	return
+}
+
+func (p *PlaceName) AddPlace(place string, frequency float64) {
+	p.places[place] = frequency
+	if l := utils.RuneLen(place); l > p.maxPlaceLen {
+		p.maxPlaceLen = l
+	}
+}
+
+func (p *PlaceName) Match(text []rune, index int) []*WordAttr {
+	found := make(map[int]float64)
+	guessed := make(map[int]bool)
+	add := func(length int, frequency float64, guess bool) {
+		if f, ok := found[length]; !ok || frequency > f {
+			found[length] = frequency
+		}
+		// 同一个长度既是已知地名又是猜测的地名时，按已知地名处理
+		if _, ok := guessed[length]; !ok || !guess {
+			guessed[length] = guess
+		}
+	}
+
+	// 地名表中的地名和已知地名加后缀
+	for l := 1; l <= p.maxPlaceLen && index+l <= len(text); l++ {
+		if freq, ok := p.places[string(text[index:(index+l)])]; ok {
+			add(l, freq, false)
+			p.matchSuffixes(text, index+l, func(sl int) { add(l+sl, p.SuffixFrequency, false) })
+		}
+	}
+	for l := 2; l <= 4 && index+l <= len(text); l++ {
+		if wa := p.wordDict.GetWordAttr(text[index:(index + l)]); wa != nil && wa.Pos&POS_A_NS != 0 {
+			p.matchSuffixes(text, index+l, func(sl int) { add(l+sl, p.SuffixFrequency, false) })
+		}
+	}
+
+	// 猜测的地名
+	if breaksWord(p.wordDict, text, index) {
+		return newCandidates(p.wordDict, text, index, found, guessed, POS_A_NS)
+	}
+	for l := 1; l <= p.MaxGuessLength && index+l < len(text); l++ {
+		if p.isGuessBase(text[index:(index+l)]) && !breaksWord(p.wordDict, text, index+l) {
+			p.matchSuffixes(text, index+l, func(sl int) { add(l+sl, p.GuessFrequency, true) })
+		}
+	}
+
+	return newCandidates(p.wordDict, text, index, found, guessed, POS_A_NS)
+}
+
+// 从 pos 开始的地名后缀，最多两层，如 某某区 + 某某村 中的 区，中关村 + 大街
+func (p *PlaceName) matchSuffixes(text []rune, pos int, handle func(length int)) {
+	for l := 1; l <= p.maxSuffixLen && pos+l <= len(text); l++ {
+		if !p.suffixes[string(text[pos:(pos+l)])] {
+			continue
+		}
+		handle(l)
+		for l2 := 1; l2 <= p.maxSuffixLen && pos+l+l2 <= len(text); l2++ {
+			if p.suffixes[string(text[(pos+l):(pos+l+l2)])] {
+				handle(l + l2)
+			}
+		}
+	}
+}
+
+// 猜测地名时后缀前面的部分：代指地名的词、名词，或者两个字以上、不以后缀开头的片段，
+// 片段里面不能有词典中的多字词和动词、方位词、量词等单字，见 isGuessFragment
+func (p *PlaceName) isGuessBase(base []rune) bool {
+	if placeHolders[string(base)] {
+		return true
+	}
+	// 以后缀开头的片段是前一个地名的一部分，如 武汉市黄陂区 中的 市黄陂
+	if len(base) < 2 || p.suffixes[string(base[:1])] {
+		return false
+	}
+	if wa := p.wordDict.GetWordAttr(base); wa != nil {
+		return wa.Pos&(POS_D_N|POS_A_NZ) != 0
+	}
+	return isGuessFragment(p.wordDict, base)
+}
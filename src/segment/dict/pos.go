@@ -1,5 +1,10 @@
 package dict
 
+import (
+	"fmt"
+	"strings"
+)
+
 const (
 	POS_D_A  = 0x40000000 //	形容词 形语素
 	POS_D_B  = 0x20000000 //	区别词 区别语素
@@ -33,3 +38,106 @@ const (
 	POS_D_K  = 0x00000002 //	后接成分
 	POS_UNK  = 0x00000000 //        未知词性
 )
+
+// 词性标注符号到词性的映射，兼容人民日报语料的标注集。
+// Ag、Ng 等大写字母开头的语素标注归入对应的词性，ad、vn 等兼类标注归入主要的词性
+var posTags = map[string]int{
+	"a": POS_D_A, "ad": POS_D_A, "an": POS_D_A, "ag": POS_D_A,
+	"b": POS_D_B, "bg": POS_D_B,
+	"c": POS_D_C,
+	"d": POS_D_D, "dg": POS_D_D,
+	"e": POS_D_E,
+	"f": POS_D_F,
+	"i": POS_D_I,
+	"l": POS_D_L,
+	"m": POS_A_M, "mg": POS_A_M,
+	"mq": POS_D_MQ,
+	"n": POS_D_N, "ng": POS_D_N, "j": POS_D_N,
+	"o": POS_D_O,
+	"p": POS_D_P,
+	"q": POS_A_Q,
+	"r": POS_D_R, "rg": POS_D_R,
+	"s": POS_D_S,
+	"t": POS_D_T, "tg": POS_D_T,
+	"u": POS_D_U,
+	"v": POS_D_V, "vd": POS_D_V, "vn": POS_D_V, "vg": POS_D_V,
+	"w": POS_D_W,
+	"x": POS_D_X, "g": POS_D_X,
+	"y": POS_D_Y, "yg": POS_D_Y,
+	"z": POS_D_Z,
+	"nr": POS_A_NR,
+	"ns": POS_A_NS,
+	"nt": POS_A_NT,
+	"nx": POS_A_NX,
+	"nz": POS_A_NZ,
+	"h": POS_D_H,
+	"k": POS_D_K,
+}
+
+// 把词性标注符号转换成词性，不认识的标注返回 POS_UNK
+func ParsePosTag(tag string) int {
+	return posTags[strings.ToLower(tag)]
+}
+
+// 每种词性对应的标注符号
+var posNames = map[int]string{
+	POS_D_A: "a", POS_D_B: "b", POS_D_C: "c", POS_D_D: "d", POS_D_E: "e", POS_D_F: "f",
+	POS_D_I: "i", POS_D_L: "l", POS_A_M: "m", POS_D_MQ: "mq", POS_D_N: "n", POS_D_O: "o",
+	POS_D_P: "p", POS_A_Q: "q", POS_D_R: "r", POS_D_S: "s", POS_D_T: "t", POS_D_U: "u",
+	POS_D_V: "v", POS_D_W: "w", POS_D_X: "x", POS_D_Y: "y", POS_D_Z: "z",
+	POS_A_NR: "nr", POS_A_NS: "ns", POS_A_NT: "nt", POS_A_NX: "nx", POS_A_NZ: "nz",
+	POS_D_H: "h", POS_D_K: "k",
+}
+
+// 单个词性的标注符号，POS_UNK 和多个词性的组合返回空字符串
+func PosTagName(pos int) string {
+	return posNames[pos]
+}
+
+// 把多个词性组合成的 pos 拆开，返回各个词性的标注符号
+func PosTagNames(pos int) []string {
+	names := []string{}
+	for bit := POS_D_A; bit > 0; bit >>= 1 {
+		if name, ok := posNames[bit]; ok && pos&bit != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// 每种词性的说明
+var posDescriptions = map[int]string{
+	POS_D_A: "形容词 形语素", POS_D_B: "区别词 区别语素", POS_D_C: "连词 连语素", POS_D_D: "副词 副语素",
+	POS_D_E: "叹词 叹语素", POS_D_F: "方位词 方位语素", POS_D_I: "成语", POS_D_L: "习语",
+	POS_A_M: "数词 数语素", POS_D_MQ: "数量词", POS_D_N: "名词 名语素", POS_D_O: "拟声词",
+	POS_D_P: "介词", POS_A_Q: "量词 量语素", POS_D_R: "代词 代语素", POS_D_S: "处所词",
+	POS_D_T: "时间词", POS_D_U: "助词 助语素", POS_D_V: "动词 动语素", POS_D_W: "标点符号",
+	POS_D_X: "非语素字", POS_D_Y: "语气词 语气语素", POS_D_Z: "状态词",
+	POS_A_NR: "人名", POS_A_NS: "地名", POS_A_NT: "机构团体", POS_A_NX: "外文字符", POS_A_NZ: "其他专名",
+	POS_D_H: "前接成分", POS_D_K: "后接成分", POS_UNK: "未知词性",
+}
+
+// 单个词性的说明，多个词性的组合返回空字符串
+func PosDescription(pos int) string {
+	return posDescriptions[pos]
+}
+
+// 把多个词性组合成的 pos 转换成以 | 分割的标注符号，如 0x101000 => n|v
+func PosString(pos int) string {
+	return strings.Join(PosTagNames(pos), "|")
+}
+
+// 解析以 |、逗号或空白分割的多个标注符号，返回它们组合成的词性，如 n|v => 0x101000
+func ParsePosTags(tags string) (int, error) {
+	pos := POS_UNK
+	for _, tag := range strings.FieldsFunc(tags, func(r rune) bool {
+		return r == '|' || r == ',' || r == ' ' || r == '\t'
+	}) {
+		p, ok := posTags[strings.ToLower(tag)]
+		if !ok {
+			return POS_UNK, fmt.Errorf("unknown pos tag: %s", tag)
+		}
+		pos |= p
+	}
+	return pos, nil
+}
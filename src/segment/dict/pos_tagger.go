@@ -0,0 +1,130 @@
+package dict
+
+import (
+	"segment/hmm"
+	"segment/utils"
+	"unicode"
+)
+
+const PosModelFileName = "PosHmm.txt"
+
+// 语料中出现次数不超过这个值的词，训练时换成它的字符特征，用来估计未登录词的词性
+const PosRareWordCount = 1
+
+// 基于 HMM 的词性标注：状态是词性的标注符号，观察值是词。
+// 模型中没有的词按字符特征(数字、英文、最后一个汉字)作为观察值
+type PosTagger struct {
+	model *hmm.Model
+}
+
+func NewPosTagger(model *hmm.Model) *PosTagger {
+	return &PosTagger{model: model}
+}
+
+// 未登录词的字符特征
+func wordFeature(word string) string {
+	runes := utils.ToRunes(word)
+	if len(runes) == 0 {
+		return "<unk>"
+	}
+	digit, latin := true, true
+	for _, r := range runes {
+		if !unicode.IsDigit(r) && r != '.' && r != '%' {
+			digit = false
+		}
+		if !unicode.Is(unicode.Latin, r) && !unicode.IsDigit(r) {
+			latin = false
+		}
+	}
+	switch {
+	case digit:
+		return "<num>"
+	case latin:
+		return "<eng>"
+	case unicode.Is(unicode.Han, runes[len(runes)-1]):
+		return "<unk:" + string(runes[len(runes)-1]) + ">"
+	}
+	return "<unk>"
+}
+
+// 从人民日报格式的语料训练词性标注模型，词性的标注符号先统一成 PosTagName 的形式，
+// 没有标注或者不认识的词性的句子跳过
+func TrainPosModel(corpusFile string) (*hmm.Model, error) {
+	count := make(map[string]int)
+	err := EachCorpusLine(corpusFile, func(words []string, tags []string) {
+		for _, word := range words {
+			count[word]++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	trainer := hmm.NewTrainer()
+	err = EachCorpusLine(corpusFile, func(words []string, tags []string) {
+		obs := make([]string, 0, len(words))
+		states := make([]string, 0, len(words))
+		for i, word := range words {
+			name := PosTagName(ParsePosTag(tags[i]))
+			if name == "" {
+				return
+			}
+			if count[word] <= PosRareWordCount {
+				word = wordFeature(word)
+			}
+			obs = append(obs, word)
+			states = append(states, name)
+		}
+		trainer.Add(obs, states)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trainer.Model(), nil
+}
+
+// 给一串连续的词标注词性，每个词只保留一个词性。
+// 词典中有词性的词只在这些词性中选择，其他的词按词的类型选择，都不确定时可以是任何词性
+func (t *PosTagger) Tag(words []*WordInfo) {
+	if len(words) == 0 {
+		return
+	}
+	obs := make([]string, len(words))
+	candidates := make([][]string, len(words))
+	for i, wi := range words {
+		obs[i] = t.observation(wi.Word)
+		candidates[i] = t.candidates(wi)
+	}
+	for i, tag := range t.model.Viterbi(obs, candidates) {
+		words[i].Pos = ParsePosTag(tag)
+	}
+}
+
+// 不考虑上下文，单独给一个词标注词性
+func (t *PosTagger) TagWord(wi *WordInfo) {
+	t.Tag([]*WordInfo{wi})
+}
+
+func (t *PosTagger) observation(word string) string {
+	if t.model.Seen(word) {
+		return word
+	}
+	if feature := wordFeature(word); t.model.Seen(feature) {
+		return feature
+	}
+	return word
+}
+
+// 词可以标注的词性，不是符号的词不会标注成标点符号
+func (t *PosTagger) candidates(wi *WordInfo) []string {
+	if pos := wi.EffectivePos(); pos != POS_UNK {
+		return PosTagNames(pos)
+	}
+	states := []string{}
+	for _, state := range t.model.States {
+		if state != PosTagName(POS_D_W) {
+			states = append(states, state)
+		}
+	}
+	return states
+}
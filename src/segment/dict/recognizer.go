@@ -0,0 +1,89 @@
+package dict
+
+import (
+	"sort"
+	"strings"
+)
+
+// 专名识别器，给出从 text[index] 开始的候选词，加入到 GetAllMatchs 的结果中
+type Recognizer interface {
+	Match(text []rune, index int) []*WordAttr
+}
+
+// 猜测出来的候选词在全文匹配中额外计算的词数，见 WordAttr.Penalty
+const GuessPenalty = 1
+
+// 常用的单字动词，很多在词典中没有或者没有标成动词，猜测的地名、字号中不会出现
+const COMMON_VERB_CHARS = "去来到在有是走跑逛住坐买卖用送开进回往离看说做吃喝玩找拿给让要会能"
+
+// 检查左右边界时考虑的词典中的词最多的字数
+const maxCrossWordLength = 4
+
+// 把识别出来的长度和词频转换成候选词，按长度排列，词典中已经有的词不再重复。
+// guessed 中的长度是猜测出来的，候选词带有 GuessPenalty，可以为 nil
+func newCandidates(wdict *WordDictionary, text []rune, index int, found map[int]float64, guessed map[int]bool, pos int) []*WordAttr {
+	lengths := make([]int, 0, len(found))
+	for l := range found {
+		lengths = append(lengths, l)
+	}
+	sort.Ints(lengths)
+
+	result := []*WordAttr{}
+	for _, l := range lengths {
+		if wdict.GetWordAttr(text[index:(index+l)]) == nil {
+			wa := NewWordAttr(string(text[index:(index+l)]), pos, found[l])
+			if guessed[l] {
+				wa.Penalty = GuessPenalty
+			}
+			result = append(result, wa)
+		}
+	}
+	return result
+}
+
+// 不在词典中的片段：里面没有词典中的多字词，也没有单字的连词、介词、代词、助词、副词、语气词，
+// 这些字不会出现在地名、字号中
+func isUnknownFragment(wdict *WordDictionary, fragment []rune) bool {
+	for i := 0; i < len(fragment); i++ {
+		for j := i + 2; j <= len(fragment); j++ {
+			if wdict.GetWordAttr(fragment[i:j]) != nil {
+				return false
+			}
+		}
+		if wa := wdict.GetWordAttr(fragment[i:(i + 1)]); wa != nil && wa.Pos&(POS_D_C|POS_D_P|POS_D_R|POS_D_U|POS_D_D|POS_D_Y) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 可以作为猜测的地名、字号、日本人名的片段：不在词典中，也没有动词、方位词、量词单字，如 里走、有个
+func isGuessFragment(wdict *WordDictionary, fragment []rune) bool {
+	if !isUnknownFragment(wdict, fragment) {
+		return false
+	}
+	for i, r := range fragment {
+		if strings.ContainsRune(COMMON_VERB_CHARS, r) {
+			return false
+		}
+		if wa := wdict.GetWordAttr(fragment[i:(i + 1)]); wa != nil && wa.Pos&(POS_D_V|POS_D_F|POS_A_Q) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// 在 text 的 pos 处断开会不会截断词典中的多字词，如 我们 中间、逛街 中间
+func breaksWord(wdict *WordDictionary, text []rune, pos int) bool {
+	if pos <= 0 || pos >= len(text) {
+		return false
+	}
+	for i := pos - 1; i >= 0 && pos-i < maxCrossWordLength; i-- {
+		for j := pos + 1; j <= len(text) && j-i <= maxCrossWordLength; j++ {
+			if wdict.GetWordAttr(text[i:j]) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -7,53 +7,129 @@
 package dict
 
 import (
+	"path/filepath"
 	"segment/utils"
+	"sort"
 	"strings"
 )
 
+// 默认的停用词表 Stopword.txt 的名字
+const DefaultStopWordList = "default"
+
+// 停用词表可以有多个，词典目录中的 Stopword.txt 是默认的表，
+// Stopword_<名字>.txt 是其他有名字的表，如 Stopword_en.txt、Stopword_punct.txt，分词时通过选项选择使用哪些表
 type StopWord struct {
 	stopWordTbl map[string]bool
+	lists       map[string]map[string]bool
 }
 
 func NewStopWord() (s *StopWord) {
 	s = &StopWord{}
 	s.stopWordTbl = make(map[string]bool)
+	s.lists = map[string]map[string]bool{DefaultStopWordList: s.stopWordTbl}
 	return
 }
 
 func (s *StopWord) Load(file string) (err error) {
+	return s.LoadList(DefaultStopWordList, file)
+}
+
+// 加载 dictPath 中所有 Stopword_<名字>.txt
+func (s *StopWord) LoadLists(dictPath string) error {
+	files, err := filepath.Glob(dictPath + "/Stopword_*.txt")
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "Stopword_"), ".txt")
+		if err = s.LoadList(name, file); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// 把文件中的词加入名为 name 的停用词表，一行一个词
+func (s *StopWord) LoadList(name string, file string) (err error) {
+	words := []string{}
 	err = utils.EachLine(file, func(line string) {
 		if len(line) > 0 {
-			if utils.FirstRune(line) < 128 {
-				s.stopWordTbl[strings.ToLower(line)] = true
-			} else {
-				s.stopWordTbl[line] = true
-			}
+			words = append(words, line)
 		}
 	})
+	if err == nil {
+		s.AddList(name, words)
+	}
 	return
 }
 
-func (s *StopWord) IsStopWord(word string, filterEnglish bool, filterEnglishLength int, filterNumeric bool, filterNumbericLength int) bool {
+// 把 words 加入名为 name 的停用词表，表不存在时新建
+func (s *StopWord) AddList(name string, words []string) {
+	tbl, ok := s.lists[name]
+	if !ok {
+		tbl = make(map[string]bool)
+		s.lists[name] = tbl
+	}
+	for _, word := range words {
+		tbl[normalizeStopWord(word)] = true
+	}
+}
+
+// 已经加载的停用词表的名字
+func (s *StopWord) ListNames() []string {
+	names := make([]string, 0, len(s.lists))
+	for name := range s.lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 是否加载了名为 name 的停用词表
+func (s *StopWord) HasList(name string) bool {
+	_, ok := s.lists[name]
+	return ok
+}
+
+// 英文不区分大小写
+func normalizeStopWord(word string) string {
+	if utils.FirstRune(word) < 128 {
+		return strings.ToLower(word)
+	}
+	return word
+}
+
+// word 是否在名为 lists 的任意一个停用词表中，没有加载的表被忽略
+func (s *StopWord) InLists(word string, lists []string) bool {
 	if len(word) == 0 {
 		return false
 	}
+	word = normalizeStopWord(word)
+	for _, name := range lists {
+		if s.lists[name][word] {
+			return true
+		}
+	}
+	return false
+}
 
+// 过滤英文、数字选项打开时，长度超过限制的英文词和数字
+func IsTooLong(word string, filterEnglish bool, filterEnglishLength int, filterNumeric bool, filterNumbericLength int) bool {
+	if len(word) == 0 {
+		return false
+	}
 	r := utils.FirstRune(word)
-	if r < 128 {
-		slen := utils.RuneLen(word)
-		if filterEnglish {
-			if slen > filterEnglishLength && (r < '0' || r > '9') {
-				return true
-			}
-		}
-		if filterNumeric {
-			if slen > filterNumbericLength && (r >= '0' && r <= '9') {
-				return true
-			}
-		}
-		return s.stopWordTbl[strings.ToLower(word)]
+	if r >= 128 {
+		return false
+	}
+	slen := utils.RuneLen(word)
+	if r >= '0' && r <= '9' {
+		return filterNumeric && slen > filterNumbericLength
 	}
+	return filterEnglish && slen > filterEnglishLength
+}
 
-	return s.stopWordTbl[word]
+// 默认停用词表中的词，以及过滤英文、数字选项打开时过长的英文词和数字
+func (s *StopWord) IsStopWord(word string, filterEnglish bool, filterEnglishLength int, filterNumeric bool, filterNumbericLength int) bool {
+	return IsTooLong(word, filterEnglish, filterEnglishLength, filterNumeric, filterNumbericLength) || s.InLists(word, []string{DefaultStopWordList})
 }
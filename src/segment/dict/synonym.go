@@ -2,70 +2,192 @@ package dict
 
 import (
 	"segment/utils"
+	"strconv"
 	"strings"
+	"unicode"
 )
 
-const SynonymFileName = "Synonym.txt"
+const (
+	SynonymFileName      = "Synonym.txt"
+	AbbreviationFileName = "Abbreviation.txt"
+)
+
+// 同义词规则，格式和 Solr/Elasticsearch 的同义词文件相同：以 “,” 分割的一组词互为同义词，如 揭穿,戳穿；
+// 带 “=>” 的是单向规则，左边的每个词扩展成右边的词，如 北大,北京大 => 北京大学。
+// 词中的 “,” 用 “\,” 表示。行尾可以用 “|权重” 给规则指定 0 到 1 之间的权重，默认为 1。
+// 规则左边的词可以是多个词组成的短语，和分词结果中连续的几个词匹配
+type synonymRule struct {
+	outputs []string
+	weight  float64
+}
+
+// 扩展出来的同义词和规则的权重
+type WeightedSynonym struct {
+	Word   string
+	Weight float64
+}
 
 type Synonym struct {
-	groupList     []([]string) //同义词组，文件中一行为一组，一组同义词以 “,” 分割
-	wordToGroupId map[string]([]int)
+	rules     map[string]([]*synonymRule) // 归一化后的输入词到规则
+	maxKeyLen int
 }
 
 func NewSynonym() *Synonym {
 	s := &Synonym{}
-	s.groupList = []([]string){}
-	s.wordToGroupId = make(map[string]([]int))
+	s.rules = make(map[string]([]*synonymRule))
 	return s
 }
 
+// 加载 Synonym.txt，以及可选的简称表 Abbreviation.txt
 func (s *Synonym) Load(dictPath string) (err error) {
-	err = utils.EachLine(dictPath+"/"+SynonymFileName, func(line string) {
-		if len(line) > 0 {
-			words := strings.Split(line, ",")
-			s.groupList = append(s.groupList, words)
-			groupId := len(s.groupList) - 1
-			for i := 0; i < len(words); i++ {
-				key := strings.TrimSpace(words[i])
-				if l, ok := s.wordToGroupId[key]; ok {
-					if l[len(l)-1] == groupId {
-						continue
-					}
-					s.wordToGroupId[key] = append(s.wordToGroupId[key], groupId)
-				} else {
-					s.wordToGroupId[key] = []int{groupId}
-				}
-			}
-		}
-	})
+	err = s.Import(dictPath + "/" + SynonymFileName)
+	if err == nil {
+		err = utils.EachLineIfExist(dictPath+"/"+AbbreviationFileName, func(line string) {
+			s.AddAbbreviation(line)
+		})
+	}
 	return
 }
 
-func (s *Synonym) GetSynonyms(text string) []string {
-	word := strings.ToLower(strings.TrimSpace(text))
-	if l, ok := s.wordToGroupId[word]; ok {
-		result := []string{}
-		for groupId := range l {
-			for _, w := range s.groupList[groupId] {
-				if w == word {
-					continue
-				}
+// 导入 Solr/Elasticsearch 格式的同义词文件，# 开头的行是注释
+func (s *Synonym) Import(file string) error {
+	return utils.EachLine(file, func(line string) {
+		s.AddRule(line)
+	})
+}
 
-				found := false
-				for _, wo := range result {
-					if w == wo {
-						found = true
-						break
-					}
-				}
-				if found {
-					continue
-				}
+// 加入一行同义词规则，格式见 synonymRule
+func (s *Synonym) AddRule(line string) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return
+	}
+	line, weight := splitWeight(line)
+
+	if i := strings.Index(line, "=>"); i >= 0 {
+		inputs, outputs := splitWords(line[:i]), splitWords(line[(i+2):])
+		if len(outputs) == 0 {
+			return
+		}
+		for _, input := range inputs {
+			s.addRule(input, outputs, weight)
+		}
+		return
+	}
+
+	words := splitWords(line)
+	for _, word := range words {
+		s.addRule(word, words, weight)
+	}
+}
+
+// 简称表一行是 简称,全称[,全称2...][|权重]，简称单向扩展成全称
+func (s *Synonym) AddAbbreviation(line string) {
+	line = strings.TrimSpace(strings.TrimPrefix(line, "\ufeff"))
+	if len(line) == 0 || strings.HasPrefix(line, "#") {
+		return
+	}
+	line, weight := splitWeight(line)
+	words := splitWords(line)
+	if len(words) > 1 {
+		s.addRule(words[0], words[1:], weight)
+	}
+}
 
-				result = append(result, w)
+func (s *Synonym) addRule(input string, outputs []string, weight float64) {
+	key := normalizeSynonymKey(input)
+	if len(key) == 0 {
+		return
+	}
+	s.rules[key] = append(s.rules[key], &synonymRule{outputs: outputs, weight: weight})
+	if l := utils.RuneLen(key); l > s.maxKeyLen {
+		s.maxKeyLen = l
+	}
+}
+
+// 同义词规则中最长的输入词的字数，用来限制和分词结果中连续的词匹配的长度
+func (s *Synonym) MaxKeyLength() int {
+	return s.maxKeyLen
+}
+
+// 去掉空白并转换成小写，new york 和分词结果中的 new/york 都归一化为 newyork
+func normalizeSynonymKey(text string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)
+}
+
+// 行尾的 |权重，没有时权重为 1
+func splitWeight(line string) (string, float64) {
+	if i := strings.LastIndex(line, "|"); i >= 0 {
+		if w, err := strconv.ParseFloat(strings.TrimSpace(line[(i+1):]), 64); err == nil {
+			return strings.TrimSpace(line[:i]), w
+		}
+	}
+	return line, 1
+}
+
+// 以 “,” 分割，“\,” 是词中的逗号
+func splitWords(text string) []string {
+	words := []string{}
+	word := []rune{}
+	runes := []rune(text)
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && runes[i] == '\\' && i+1 < len(runes) {
+			i++
+			word = append(word, runes[i])
+			continue
+		}
+		if i < len(runes) && runes[i] != ',' {
+			word = append(word, runes[i])
+			continue
+		}
+		if w := strings.TrimSpace(string(word)); len(w) > 0 {
+			words = append(words, w)
+		}
+		word = word[:0]
+	}
+	return words
+}
+
+// text 的所有同义词和规则的权重，同一个同义词出现在多条规则中时取最大的权重
+func (s *Synonym) GetWeightedSynonyms(text string) []WeightedSynonym {
+	key := normalizeSynonymKey(text)
+	rules, ok := s.rules[key]
+	if !ok {
+		return nil
+	}
+	result := []WeightedSynonym{}
+	index := make(map[string]int)
+	for _, rule := range rules {
+		for _, w := range rule.outputs {
+			if normalizeSynonymKey(w) == key {
+				continue
 			}
+			if i, found := index[w]; found {
+				if rule.weight > result[i].Weight {
+					result[i].Weight = rule.weight
+				}
+				continue
+			}
+			index[w] = len(result)
+			result = append(result, WeightedSynonym{Word: w, Weight: rule.weight})
 		}
-		return result
 	}
-	return nil
+	return result
+}
+
+func (s *Synonym) GetSynonyms(text string) []string {
+	synonyms := s.GetWeightedSynonyms(text)
+	if synonyms == nil {
+		return nil
+	}
+	result := make([]string, len(synonyms))
+	for i, syn := range synonyms {
+		result[i] = syn.Word
+	}
+	return result
 }
@@ -0,0 +1,126 @@
+package dict
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestSynonym(lines ...string) *Synonym {
+	s := NewSynonym()
+	for _, line := range lines {
+		s.AddRule(line)
+	}
+	return s
+}
+
+func synonymString(synonyms []WeightedSynonym) string {
+	words := []string{}
+	for _, syn := range synonyms {
+		words = append(words, syn.Word)
+	}
+	return strings.Join(words, ",")
+}
+
+// 一个词在两组同义词中，两组的其他词都要扩展出来
+func TestSynonymInTwoGroups(t *testing.T) {
+	s := newTestSynonym("粗俗,粗鄙", "粗鄙,粗野,粗鲁")
+	cases := map[string]string{
+		"粗鄙": "粗俗,粗野,粗鲁",
+		"粗俗": "粗鄙",
+		"粗鲁": "粗鄙,粗野",
+	}
+	for word, expected := range cases {
+		if got := synonymString(s.GetWeightedSynonyms(word)); got != expected {
+			t.Errorf("%s: got %s, want %s", word, got, expected)
+		}
+	}
+}
+
+// 单向规则只从左边扩展到右边
+func TestSynonymOneWay(t *testing.T) {
+	s := newTestSynonym("北大,北京大 => 北京大学")
+	if got := s.GetSynonyms("北大"); len(got) != 1 || got[0] != "北京大学" {
+		t.Errorf("北大: got %v, want [北京大学]", got)
+	}
+	if got := s.GetSynonyms("北京大"); len(got) != 1 || got[0] != "北京大学" {
+		t.Errorf("北京大: got %v, want [北京大学]", got)
+	}
+	if got := s.GetSynonyms("北京大学"); got != nil {
+		t.Errorf("北京大学: got %v, want nil", got)
+	}
+}
+
+// 短语的输入去掉空白并转成小写后匹配
+func TestSynonymPhrase(t *testing.T) {
+	s := newTestSynonym("New York, NYC, 纽约")
+	if got := synonymString(s.GetWeightedSynonyms("new york")); got != "NYC,纽约" {
+		t.Errorf("new york: got %s, want NYC,纽约", got)
+	}
+	if got := synonymString(s.GetWeightedSynonyms("newyork")); got != "NYC,纽约" {
+		t.Errorf("newyork: got %s, want NYC,纽约", got)
+	}
+	if got := s.MaxKeyLength(); got != 7 {
+		t.Errorf("MaxKeyLength: got %d, want 7", got)
+	}
+}
+
+func TestSynonymWeight(t *testing.T) {
+	s := newTestSynonym(
+		"揭穿,戳穿|0.8",
+		"揭穿,揭露 | 0.5",
+		"揭穿 => 戳穿|0.9", // 同一个同义词取最大的权重
+		"a\\,b,c|x",    // 不是数字的不当作权重
+	)
+	cases := []struct {
+		word     string
+		expected []WeightedSynonym
+	}{
+		{"揭穿", []WeightedSynonym{{"戳穿", 0.9}, {"揭露", 0.5}}},
+		{"揭露", []WeightedSynonym{{"揭穿", 0.5}}},
+		{"a,b", []WeightedSynonym{{"c|x", 1}}},
+	}
+	for _, tc := range cases {
+		got := s.GetWeightedSynonyms(tc.word)
+		if len(got) != len(tc.expected) {
+			t.Errorf("%s: got %v, want %v", tc.word, got, tc.expected)
+			continue
+		}
+		for i := range got {
+			if got[i].Word != tc.expected[i].Word || math.Abs(got[i].Weight-tc.expected[i].Weight) > 1e-9 {
+				t.Errorf("%s: got %v, want %v", tc.word, got, tc.expected)
+				break
+			}
+		}
+	}
+}
+
+// 注释和空行跳过，简称表的简称单向扩展成全称
+func TestSynonymLoad(t *testing.T) {
+	dir := t.TempDir()
+	synonyms := "\ufeff# 注释\n\n揭穿,戳穿\n"
+	if err := os.WriteFile(filepath.Join(dir, SynonymFileName), []byte(synonyms), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, AbbreviationFileName), []byte("北大,北京大学|0.6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewSynonym()
+	if err := s.Load(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.GetSynonyms("揭穿"); len(got) != 1 || got[0] != "戳穿" {
+		t.Errorf("揭穿: got %v, want [戳穿]", got)
+	}
+	if got := s.GetWeightedSynonyms("北大"); len(got) != 1 || got[0].Word != "北京大学" || got[0].Weight != 0.6 {
+		t.Errorf("北大: got %v, want [{北京大学 0.6}]", got)
+	}
+	if got := s.GetSynonyms("北京大学"); got != nil {
+		t.Errorf("北京大学: got %v, want nil", got)
+	}
+	if got := s.GetSynonyms("# 注释"); got != nil {
+		t.Errorf("comment: got %v, want nil", got)
+	}
+}
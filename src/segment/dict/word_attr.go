@@ -4,8 +4,10 @@ type WordAttr struct {
 	Word      string
 	Pos       int
 	Frequency float64
+	Score     float64 // 识别出的人名的得分，词典中的词为 0
+	Penalty   int     // 猜测出来的候选词在全文匹配中额外计算的词数，词典中的词为 0
 }
 
 func NewWordAttr(word string, pos int, frequency float64) *WordAttr {
-	return &WordAttr{word, pos, frequency}
+	return &WordAttr{Word: word, Pos: pos, Frequency: frequency}
 }
@@ -2,6 +2,7 @@ package dict
 
 import (
 	"container/list"
+	"segment/hmm"
 	"segment/utils"
 	"strconv"
 	"strings"
@@ -23,7 +24,11 @@ type WordDictionary struct {
 	firstCharDict  map[rune](*WordAttr)
 	doubleCharDict map[int32](*WordAttr)
 	tripleCharDict map[int64](*[]byte)
+	totalFrequency float64
 	ChineseName    *ChsName
+	Bigram         *Bigram
+	// 未登录词识别用的 BMES 字标注模型
+	UnknownWordModel *hmm.Model
 }
 
 func NewWordDictionary() *WordDictionary {
@@ -41,7 +46,9 @@ func (d *WordDictionary) Load(fileName string) (err error) {
 		return err
 	}
 
+	d.totalFrequency = 0
 	for e := waList.Front(); e != nil; e = e.Next() {
+		d.totalFrequency += e.Value.(*WordAttr).Frequency
 		key := strings.ToLower(e.Value.(*WordAttr).Word)
 		runes := utils.ToRunes(key)
 
@@ -103,6 +110,11 @@ func (d *WordDictionary) loadFromTextFile(fileName string) (dicts *list.List, er
 	return
 }
 
+// 词典中所有词的词频之和，用于把词频换算成概率
+func (d *WordDictionary) TotalFrequency() float64 {
+	return d.totalFrequency
+}
+
 func (d *WordDictionary) GetWordAttr(word []rune) *WordAttr {
 	if len(word) == 1 {
 		if wa, ok := d.firstCharDict[word[0]]; ok {
@@ -121,7 +133,8 @@ func (d *WordDictionary) GetWordAttr(word []rune) *WordAttr {
 	return nil
 }
 
-func (d *WordDictionary) GetAllMatchs(text string, chineseNameIdentify bool) (result []PositionLength) {
+// 匹配 text 中所有词典中的词，chineseNameIdentify 为 true 时加入人名，recognizers 识别出的专名也作为候选词加入
+func (d *WordDictionary) GetAllMatchs(text string, chineseNameIdentify bool, recognizers ...Recognizer) (result []PositionLength) {
 	result = []PositionLength{}
 	if len(text) == 0 {
 		return
@@ -139,10 +152,15 @@ func (d *WordDictionary) GetAllMatchs(text string, chineseNameIdentify bool) (re
 
 		var chsNames []string = nil
 		if chineseNameIdentify {
-			chsNames = d.ChineseName.Match(rtext, i)
-			for _, name := range chsNames {
-				wa := NewWordAttr(name, POS_A_NR, 0)
-				result = append(result, PositionLength{0, i, utils.RuneLen(name), wa})
+			for _, wa := range d.ChineseName.MatchScored(rtext, i) {
+				chsNames = append(chsNames, wa.Word)
+				result = append(result, PositionLength{0, i, utils.RuneLen(wa.Word), wa})
+			}
+		}
+
+		for _, recognizer := range recognizers {
+			for _, wa := range recognizer.Match(rtext, i) {
+				result = append(result, PositionLength{0, i, utils.RuneLen(wa.Word), wa})
 			}
 		}
 
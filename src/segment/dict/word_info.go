@@ -8,7 +8,10 @@ const (
 	TNumeric = 4
 	TSymbol  = 5
 	TSpace   = 6
-	TSynonym = 7 //同义词
+	TSynonym = 7  //同义词
+	TEmoji   = 8  //表情符号
+	TKana    = 9  //日文假名
+	THangul  = 10 //韩文
 )
 
 type WordInfo struct {
@@ -19,6 +22,9 @@ type WordInfo struct {
 	OriginalWordType int
 	Position         int
 	Rank             int
+	Annotation       string  // 附加说明，如表情符号的简短名称
+	OriginalLength   int     // 词在原文中对应的字符数，词干、同义词等和词本身的长度不一定相同
+	Score            float64 // 识别出的人名的得分（见 ChsName.Score），或者同义词规则的权重
 }
 
 func NewWordInfo(word string, position int, pos int, frequency float64, rank int, wordType int, originalWordType int) *WordInfo {
@@ -32,3 +38,19 @@ func NewWordInfoDefault() *WordInfo {
 func NewWordInfoSome(word string, pos int, frequency float64) *WordInfo {
     return &WordInfo{Word: word, Pos: pos, Frequency: frequency}
 }
+
+// 词的词性，没有词性时按词的类型推断：数字是数词，英文是外文字符，符号是标点符号
+func (w *WordInfo) EffectivePos() int {
+	if w.Pos != POS_UNK {
+		return w.Pos
+	}
+	switch w.WordType {
+	case TNumeric:
+		return POS_A_M
+	case TEnglish:
+		return POS_A_NX
+	case TSymbol:
+		return POS_D_W
+	}
+	return POS_UNK
+}
@@ -0,0 +1,240 @@
+/**
+ * func:  segmentation accuracy evaluation against gold corpora
+ *
+ * gold corpus in SIGHAN bakeoff format: one sentence per line, words separated by spaces.
+ * a word is correct when both of its boundaries match a gold word.
+ */
+
+package eval
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/utils"
+	"sort"
+	"strings"
+)
+
+// 一种分词错误：标准答案中的一段和分词结果中对应的一段，以空格分割词
+type ErrorPattern struct {
+	Gold      string `json:"gold"`
+	Predicted string `json:"predicted"`
+	Count     int    `json:"count"`
+}
+
+type Result struct {
+	Lines      int
+	Gold       int // 标准答案中的词数
+	Predicted  int // 分词结果中的词数
+	Correct    int // 切分正确的词数
+	GoldOOV    int // 标准答案中的未登录词数
+	CorrectOOV int
+	GoldIV     int // 标准答案中的登录词数
+	CorrectIV  int
+	errors     map[ErrorPattern]int
+}
+
+func NewResult() *Result {
+	return &Result{errors: make(map[ErrorPattern]int)}
+}
+
+// 比较一个句子的标准答案和分词结果，两者连接起来必须是同一个句子。
+// isKnown 判断一个词是否在词典中，用来统计未登录词和登录词的召回率
+func (r *Result) Add(gold []string, predicted []string, isKnown func(word string) bool) {
+	r.Lines++
+	r.Gold += len(gold)
+	r.Predicted += len(predicted)
+
+	goldSpans := toSpans(gold)
+	predSpans := toSpans(predicted)
+	predSet := make(map[[2]int]bool, len(predSpans))
+	for _, span := range predSpans {
+		predSet[span] = true
+	}
+
+	for i, span := range goldSpans {
+		known := isKnown != nil && isKnown(gold[i])
+		if known {
+			r.GoldIV++
+		} else {
+			r.GoldOOV++
+		}
+		if predSet[span] {
+			r.Correct++
+			if known {
+				r.CorrectIV++
+			} else {
+				r.CorrectOOV++
+			}
+		}
+	}
+
+	r.addErrors(gold, goldSpans, predicted, predSpans)
+}
+
+// 两边的词边界重新对齐时，中间不一致的一段就是一个错误
+func (r *Result) addErrors(gold []string, goldSpans [][2]int, predicted []string, predSpans [][2]int) {
+	i, j := 0, 0
+	for i < len(goldSpans) && j < len(predSpans) {
+		if goldSpans[i] == predSpans[j] {
+			i++
+			j++
+			continue
+		}
+		gi, pj := i, j
+		for goldSpans[i][1] != predSpans[j][1] {
+			if goldSpans[i][1] < predSpans[j][1] {
+				i++
+			} else {
+				j++
+			}
+			if i >= len(goldSpans) || j >= len(predSpans) {
+				return
+			}
+		}
+		i++
+		j++
+		pattern := ErrorPattern{Gold: strings.Join(gold[gi:i], " "), Predicted: strings.Join(predicted[pj:j], " ")}
+		r.errors[pattern]++
+	}
+}
+
+func toSpans(words []string) [][2]int {
+	spans := make([][2]int, len(words))
+	pos := 0
+	for i, word := range words {
+		l := utils.RuneLen(word)
+		spans[i] = [2]int{pos, pos + l}
+		pos += l
+	}
+	return spans
+}
+
+func ratio(a int, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+func (r *Result) Precision() float64 {
+	return ratio(r.Correct, r.Predicted)
+}
+
+func (r *Result) Recall() float64 {
+	return ratio(r.Correct, r.Gold)
+}
+
+func (r *Result) F1() float64 {
+	p, q := r.Precision(), r.Recall()
+	if p+q == 0 {
+		return 0
+	}
+	return 2 * p * q / (p + q)
+}
+
+// 标准答案中未登录词所占的比例
+func (r *Result) OOVRate() float64 {
+	return ratio(r.GoldOOV, r.Gold)
+}
+
+func (r *Result) OOVRecall() float64 {
+	return ratio(r.CorrectOOV, r.GoldOOV)
+}
+
+func (r *Result) IVRecall() float64 {
+	return ratio(r.CorrectIV, r.GoldIV)
+}
+
+// 出现次数最多的 n 种错误，n <= 0 时返回全部
+func (r *Result) TopErrors(n int) []ErrorPattern {
+	patterns := make([]ErrorPattern, 0, len(r.errors))
+	for p, c := range r.errors {
+		p.Count = c
+		patterns = append(patterns, p)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		if patterns[i].Gold != patterns[j].Gold {
+			return patterns[i].Gold < patterns[j].Gold
+		}
+		return patterns[i].Predicted < patterns[j].Predicted
+	})
+	if n > 0 && len(patterns) > n {
+		patterns = patterns[:n]
+	}
+	return patterns
+}
+
+// 用于回归跟踪的评测报告
+type Report struct {
+	Lines     int            `json:"lines"`
+	Gold      int            `json:"gold_words"`
+	Predicted int            `json:"predicted_words"`
+	Correct   int            `json:"correct_words"`
+	Precision float64        `json:"precision"`
+	Recall    float64        `json:"recall"`
+	F1        float64        `json:"f1"`
+	OOVRate   float64        `json:"oov_rate"`
+	OOVRecall float64        `json:"oov_recall"`
+	IVRecall  float64        `json:"iv_recall"`
+	Options   interface{}    `json:"options,omitempty"`
+	TopErrors []ErrorPattern `json:"top_errors"`
+}
+
+func (r *Result) Report(topErrors int) *Report {
+	return &Report{
+		Lines:     r.Lines,
+		Gold:      r.Gold,
+		Predicted: r.Predicted,
+		Correct:   r.Correct,
+		Precision: r.Precision(),
+		Recall:    r.Recall(),
+		F1:        r.F1(),
+		OOVRate:   r.OOVRate(),
+		OOVRecall: r.OOVRecall(),
+		IVRecall:  r.IVRecall(),
+		TopErrors: r.TopErrors(topErrors),
+	}
+}
+
+// 把分词结果转换成覆盖整个句子、互不重叠的词序列。
+// 多元分词、同义词等输出的词会和其他词重叠，同一位置开始的词取最长的一个；
+// 被过滤掉而没有被任何词覆盖的字，连续的作为一个词
+func Tokens(wordInfoList *list.List, text string) []string {
+	runes := utils.ToRunes(text)
+	spans := [][2]int{}
+	for cur := wordInfoList.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		length := wi.OriginalLength
+		if wi.WordType == dict.TSynonym || length == 0 || wi.Position+length > len(runes) {
+			continue
+		}
+		spans = append(spans, [2]int{wi.Position, wi.Position + length})
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i][0] != spans[j][0] {
+			return spans[i][0] < spans[j][0]
+		}
+		return spans[i][1] > spans[j][1]
+	})
+
+	tokens := []string{}
+	pos := 0
+	for _, span := range spans {
+		if span[0] < pos {
+			continue
+		}
+		if span[0] > pos {
+			tokens = append(tokens, string(runes[pos:span[0]]))
+		}
+		tokens = append(tokens, string(runes[span[0]:span[1]]))
+		pos = span[1]
+	}
+	if pos < len(runes) {
+		tokens = append(tokens, string(runes[pos:]))
+	}
+	return tokens
+}
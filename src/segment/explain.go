@@ -0,0 +1,77 @@
+package segment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"segment/dict"
+	"segment/match"
+	"segment/utils"
+)
+
+// 分词过程的解释：每段中文的候选词、孤立点分割的片段、各条路径的指标、选出的组合、
+// 未登录词标记，以及最终的分词结果
+type Explanation struct {
+	Text   string              `json:"text"`   // 分词的文本，打开归一化选项时是归一化之后的文本，Blocks 中的位置对应这个文本
+	Blocks []*match.MatchTrace `json:"blocks"` // 每段中文的匹配过程
+	Result []*dict.WordInfo    `json:"result"` // 最终的分词结果，位置对应原文
+}
+
+// 分词并记录分词过程
+func (s *Segment) Explain(text string, options *match.MatchOptions, params *match.MatchParameter) *Explanation {
+	e := &Explanation{Text: text, Blocks: []*match.MatchTrace{}, Result: []*dict.WordInfo{}}
+	s.explanation = e
+	defer func() { s.explanation = nil }()
+	result := s.DoSegmentWithOptionParam(text, options, params)
+
+	for cur := result.Front(); cur != nil; cur = cur.Next() {
+		e.Result = append(e.Result, cur.Value.(*dict.WordInfo))
+	}
+	return e
+}
+
+func (e *Explanation) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "  ")
+}
+
+// 导出 Graphviz DOT 格式的候选词网格：每段中文一个子图，节点是字之间的位置，边是候选词，
+// 匹配器选出的词用红色粗线表示，不在候选词中的输出（如未登录词）用虚线表示
+func (e *Explanation) Dot() string {
+	var buf bytes.Buffer
+	buf.WriteString("digraph lattice {\n\trankdir=LR;\n\tnode [shape=circle, fontsize=10];\n")
+	for i, block := range e.Blocks {
+		runes := utils.ToRunes(block.Text)
+		fmt.Fprintf(&buf, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, fmt.Sprintf("%s @%d (%s)", block.Text, block.Offset, block.Matcher))
+		for p := 0; p <= len(runes); p++ {
+			fmt.Fprintf(&buf, "\t\tb%d_%d [label=\"%d\"];\n", i, p, block.Offset+p)
+		}
+
+		chosen := make(map[[2]int]bool)
+		for _, w := range block.Result {
+			chosen[[2]int{w.Position, w.Length}] = true
+		}
+		candidates := make(map[[2]int]bool)
+		for _, w := range block.Candidates {
+			key := [2]int{w.Position, w.Length}
+			if candidates[key] {
+				continue
+			}
+			candidates[key] = true
+			style := ""
+			if chosen[key] {
+				style = ", color=red, penwidth=2"
+			}
+			fmt.Fprintf(&buf, "\t\tb%d_%d -> b%d_%d [label=%q%s];\n", i, w.Position, i, w.Position+w.Length, fmt.Sprintf("%s %g", w.Word, w.Frequency), style)
+		}
+		for _, w := range block.Result {
+			key := [2]int{w.Position, w.Length}
+			if !candidates[key] {
+				candidates[key] = true
+				fmt.Fprintf(&buf, "\t\tb%d_%d -> b%d_%d [label=%q, color=red, style=dashed];\n", i, w.Position, i, w.Position+w.Length, w.Word)
+			}
+		}
+		buf.WriteString("\t}\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
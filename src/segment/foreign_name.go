@@ -0,0 +1,37 @@
+package segment
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/utils"
+)
+
+// 词法分析把 伊丽莎白·泰勒 分成 伊丽莎白、·、泰勒 三段，分隔符两边都是音译用字时
+// 把三段合成一段中文，由外国人名识别器给出整个人名的候选词
+func (s *Segment) joinForeignNames(words *list.List) {
+	cur := words.Front()
+	for cur != nil {
+		wi := cur.Value.(*dict.WordInfo)
+		sep := cur.Next()
+		if wi.WordType != dict.TSimplifiedChinese || sep == nil || sep.Next() == nil {
+			cur = cur.Next()
+			continue
+		}
+		sepWord := sep.Value.(*dict.WordInfo).Word
+		next := sep.Next().Value.(*dict.WordInfo)
+		if utils.RuneLen(sepWord) != 1 || !dict.IsForeignNameSeparator(utils.FirstRune(sepWord)) || next.WordType != dict.TSimplifiedChinese {
+			cur = cur.Next()
+			continue
+		}
+
+		left, right := utils.ToRunes(wi.Word), utils.ToRunes(next.Word)
+		if !s.foreignName.IsTransliteration(string(left[len(left)-1:])) || !s.foreignName.IsTransliteration(string(right[:1])) {
+			cur = cur.Next()
+			continue
+		}
+		// 合并后继续看后面是否还有分隔符，如 约翰·菲茨杰拉德·肯尼迪
+		wi.Word += sepWord + next.Word
+		words.Remove(sep.Next())
+		words.Remove(sep)
+	}
+}
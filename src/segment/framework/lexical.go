@@ -3,6 +3,7 @@ package framework
 import (
 	"segment/dict"
 	"sort"
+	"unicode"
 )
 
 const (
@@ -20,6 +21,9 @@ const (
 	OutputSpace      = 3
 	OutputNumeric    = 4
 	OutputChinese    = 5
+	OutputEmoji      = 6
+	OutputKana       = 7
+	OutputHangul     = 8
 	Other            = 255
 )
 
@@ -30,9 +34,16 @@ type State struct {
 	IsQuitState     bool
 	NextStateIdDict map[rune]int
 	NextStateIds    []int
+	NextStateTables []StateTable
 	ElseStateId     int
 }
 
+// 按 unicode 字符集合跳转，用于码位分散或者范围太大不适合逐个登记的字符类
+type StateTable struct {
+	Table     *unicode.RangeTable
+	NextState int
+}
+
 func NewState(id int, isQuit bool, function int, nextStateIdDict map[rune]int) (s *State) {
 	s = &State{Id: id, IsQuitState: isQuit, Func: function, NextStateIdDict: nextStateIdDict}
 	s.NoFunction = (s.Func == None)
@@ -105,47 +116,37 @@ func (s *State) AddElseState(nextstate int) {
 	s.ElseStateId = nextstate
 }
 
-func (s *State) NextState(action rune) (nextstate int, isElseAction bool) {
-	isElseAction = false
-	nextstate = -1
+func (s *State) AddNextStateTable(table *unicode.RangeTable, nextstate int) {
+	s.NextStateTables = append(s.NextStateTables, StateTable{table, nextstate})
+}
 
+func (s *State) NextState(action rune) (nextstate int, isElseAction bool) {
 	if action < 0 {
-		isElseAction = true
-		return s.ElseStateId, isElseAction
+		return s.ElseStateId, true
 	}
 
+	nextstate = -1
 	if s.NextStateIdDict != nil {
-		if nextstate, ok := s.NextStateIdDict[action]; ok {
-			if nextstate < 0 {
-				isElseAction = true
-				return s.ElseStateId, isElseAction
-			} else {
-				return nextstate, isElseAction
-			}
-		} else {
-			isElseAction = true
-			return s.ElseStateId, isElseAction
-		}
-	} else {
-		if s.NextStateIds == nil {
-			isElseAction = true
-			return s.ElseStateId, isElseAction
+		if next, ok := s.NextStateIdDict[action]; ok {
+			nextstate = next
 		}
+	} else if int(action) < len(s.NextStateIds) {
+		nextstate = s.NextStateIds[action]
+	}
 
-		if int(action) >= len(s.NextStateIds) {
-			isElseAction = true
-			return s.ElseStateId, isElseAction
-		} else {
-			nextstate = s.NextStateIds[action]
-			if nextstate < 0 {
-				isElseAction = true
-				return s.ElseStateId, isElseAction
-			} else {
-				return nextstate, isElseAction
+	if nextstate < 0 {
+		for _, st := range s.NextStateTables {
+			if unicode.Is(st.Table, action) {
+				nextstate = st.NextState
+				break
 			}
 		}
 	}
-	return
+
+	if nextstate < 0 {
+		return s.ElseStateId, true
+	}
+	return nextstate, false
 }
 
 func (s *State) DoThings(action rune, dfa *Lexical) {
@@ -166,9 +167,21 @@ func (s *State) DoThings(action rune, dfa *Lexical) {
 		dfa.OutputToken = dict.NewWordInfoDefault()
 		s.getTextElse(dfa)
 		dfa.OutputToken.WordType = dict.TSimplifiedChinese
+	case OutputEmoji:
+		dfa.OutputToken = dict.NewWordInfoDefault()
+		s.getTextElse(dfa)
+		dfa.OutputToken.WordType = dict.TEmoji
+	case OutputKana:
+		dfa.OutputToken = dict.NewWordInfoDefault()
+		s.getTextElse(dfa)
+		dfa.OutputToken.WordType = dict.TKana
+	case OutputHangul:
+		dfa.OutputToken = dict.NewWordInfoDefault()
+		s.getTextElse(dfa)
+		dfa.OutputToken.WordType = dict.THangul
 	case Other:
 		dfa.OutputToken = dict.NewWordInfoDefault()
-		s.getText(dfa)
+		s.getTextElse(dfa)
 		dfa.OutputToken.WordType = dict.TSymbol
 	}
 }
@@ -180,23 +193,11 @@ func (s *State) getTextElse(dfa *Lexical) {
 	dfa.beginIndex = endIndex
 }
 
-func (s *State) getText(dfa *Lexical) {
-	endIndex := dfa.CurrentToken
-	if endIndex == len(dfa.inputText) {
-		dfa.OutputToken.Position = dfa.beginIndex
-		dfa.OutputToken.Word = string(dfa.inputText[dfa.beginIndex:endIndex])
-	} else {
-		dfa.OutputToken.Position = dfa.beginIndex
-		dfa.OutputToken.Word = string(dfa.inputText[dfa.beginIndex:(endIndex + 1)])
-	}
-	dfa.beginIndex = endIndex + 1
-}
-
 // static 
 var states = []*State{}
 var EofAction rune = 0
 var s0 = addState(NewStateId(0))                        // Start state
-var sother = addState(NewStateNoDict(255, true, Other)) // Start state
+var sother = addState(NewStateNoDict(255, true, Other)) // Symbol quit state
 
 func init() {
 	initDFAStates()
@@ -207,7 +208,11 @@ func initDFAStates() {
 	initSpaceStates()
 	initNumericStates()
 	initChineseStates()
+	initKanaStates()
+	initHangulStates()
+	initEmojiStates()
 	initOtherStates()
+	initKeycapStates()
 }
 
 func initIdentifierStates() {
@@ -221,6 +226,9 @@ func initIdentifierStates() {
 	s0.AddNextStateFromTo('ａ', 'ｚ', s1.Id)
 	s0.AddNextStateFromTo('Ａ', 'Ｚ', s1.Id)
 
+	// s0 [letter] s1
+	s0.AddNextStateTable(letter, s1.Id)
+
 	// s1 [_a-zA-Z0-9] s1
 	s1.AddNextState('_', s1.Id)
 	s1.AddNextStateFromTo('a', 'z', s1.Id)
@@ -230,6 +238,11 @@ func initIdentifierStates() {
 	s1.AddNextStateFromTo('Ａ', 'Ｚ', s1.Id)
 	s1.AddNextStateFromTo('０', '９', s1.Id)
 
+	// s1 [letter mark] s1
+	s1.AddNextStateTable(letter, s1.Id)
+	s1.AddNextStateTable(unicode.Mn, s1.Id)
+	s1.AddNextStateTable(unicode.Mc, s1.Id)
+
 	// s1 ^[_z-zA-Z0-9] s2
 	s1.AddElseState(s2.Id)
 }
@@ -287,8 +300,290 @@ func initChineseStates() {
 	s8.AddElseState(s9.Id)
 }
 
+// 除中日韩文字以外的 unicode 字母，中日韩文字由各自的状态处理
+var letter = excludeRangeTable(unicode.L, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Bopomofo, prolongedSoundMark, kanaVoicedMark)
+
+func excludeRangeTable(table *unicode.RangeTable, excludes ...*unicode.RangeTable) *unicode.RangeTable {
+	result := &unicode.RangeTable{}
+	var lo, hi rune = -1, -1
+	flush := func() {
+		if lo < 0 {
+			return
+		}
+		if hi <= 0xffff {
+			result.R16 = append(result.R16, unicode.Range16{Lo: uint16(lo), Hi: uint16(hi), Stride: 1})
+		} else {
+			result.R32 = append(result.R32, unicode.Range32{Lo: uint32(lo), Hi: uint32(hi), Stride: 1})
+		}
+		lo, hi = -1, -1
+	}
+	add := func(r rune) {
+		for _, ex := range excludes {
+			if unicode.Is(ex, r) {
+				flush()
+				return
+			}
+		}
+		if lo >= 0 && r == hi+1 && (r <= 0xffff || hi > 0xffff) {
+			hi = r
+			return
+		}
+		flush()
+		lo, hi = r, r
+	}
+	for _, rg := range table.R16 {
+		for r := rune(rg.Lo); r <= rune(rg.Hi); r += rune(rg.Stride) {
+			add(r)
+		}
+	}
+	for _, rg := range table.R32 {
+		for r := rune(rg.Lo); r <= rune(rg.Hi); r += rune(rg.Stride) {
+			add(r)
+		}
+	}
+	flush()
+	for _, rg := range result.R16 {
+		if rg.Hi <= unicode.MaxLatin1 {
+			result.LatinOffset++
+		}
+	}
+	return result
+}
+
+// 长音符 ー 和半角 ｰ 既可以跟在平假名后面也可以跟在片假名后面
+var prolongedSoundMark = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x30fc, 0x30fc, 1},
+		{0xff70, 0xff70, 1},
+	},
+}
+
+// 浊音、半浊音符号，包括组合形式和半角形式
+var kanaVoicedMark = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x3099, 0x309c, 1},
+		{0xff9e, 0xff9f, 1},
+	},
+}
+
+func initKanaStates() {
+	s15 := addState(NewStateIdQuit(15, false))            // Hiragana begin state
+	s16 := addState(NewStateIdQuit(16, false))            // Katakana begin state
+	s17 := addState(NewStateNoDict(17, true, OutputKana)) // Kana quit state
+
+	// 平假名和片假名交界处一般是词的边界，所以分别成词
+	// s0 [hiragana] s15
+	s0.AddNextStateTable(unicode.Hiragana, s15.Id)
+
+	// s15 [hiragana ー ゛] s15
+	s15.AddNextStateTable(unicode.Hiragana, s15.Id)
+	s15.AddNextStateTable(prolongedSoundMark, s15.Id)
+	s15.AddNextStateTable(kanaVoicedMark, s15.Id)
+
+	// s15 else s17
+	s15.AddElseState(s17.Id)
+
+	// s0 [katakana ー] s16
+	s0.AddNextStateTable(unicode.Katakana, s16.Id)
+	s0.AddNextStateTable(prolongedSoundMark, s16.Id)
+
+	// s16 [katakana ー ゛] s16
+	s16.AddNextStateTable(unicode.Katakana, s16.Id)
+	s16.AddNextStateTable(prolongedSoundMark, s16.Id)
+	s16.AddNextStateTable(kanaVoicedMark, s16.Id)
+
+	// s16 else s17
+	s16.AddElseState(s17.Id)
+}
+
+func initHangulStates() {
+	s18 := addState(NewStateIdQuit(18, false))              // Hangul begin state
+	s19 := addState(NewStateNoDict(19, true, OutputHangul)) // Hangul quit state
+
+	// s0 [hangul] s18
+	s0.AddNextStateTable(unicode.Hangul, s18.Id)
+
+	// s18 [hangul] s18
+	s18.AddNextStateTable(unicode.Hangul, s18.Id)
+
+	// s18 else s19
+	s18.AddElseState(s19.Id)
+}
+
+// 组合附加符、变体选择符、零宽连接符等，不能单独成字，总是附着在前一个字符上
+var graphemeExtend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x200d, 0x200d, 1}, // ZWJ
+		{0x20e3, 0x20e3, 1}, // 组合键帽
+		{0xfe00, 0xfe0f, 1}, // 变体选择符
+	},
+	R32: []unicode.Range32{
+		{0x1f3fb, 0x1f3ff, 1}, // 肤色修饰符
+		{0xe0020, 0xe007f, 1}, // 标签字符
+	},
+}
+
+// 表情符号变体后缀：变体选择符、肤色修饰符、标签字符、组合键帽
+var emojiExtend = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x20e3, 0x20e3, 1},
+		{0xfe00, 0xfe0f, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1f3fb, 0x1f3ff, 1},
+		{0xe0020, 0xe007f, 1},
+	},
+}
+
+var emojiBase = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x203c, 0x203c, 1},
+		{0x2049, 0x2049, 1},
+		{0x231a, 0x231b, 1},
+		{0x2328, 0x2328, 1},
+		{0x23e9, 0x23f3, 1},
+		{0x23f8, 0x23fa, 1},
+		{0x25aa, 0x25ab, 1},
+		{0x25b6, 0x25b6, 1},
+		{0x25c0, 0x25c0, 1},
+		{0x25fb, 0x25fe, 1},
+		{0x2600, 0x27bf, 1},
+		{0x2934, 0x2935, 1},
+		{0x2b05, 0x2b07, 1},
+		{0x2b1b, 0x2b1c, 1},
+		{0x2b50, 0x2b50, 1},
+		{0x2b55, 0x2b55, 1},
+		{0x3030, 0x3030, 1},
+		{0x303d, 0x303d, 1},
+		{0x3297, 0x3297, 1},
+		{0x3299, 0x3299, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1f004, 0x1f004, 1},
+		{0x1f0cf, 0x1f0cf, 1},
+		{0x1f170, 0x1f1ff, 1},
+		{0x1f200, 0x1f2ff, 1},
+		{0x1f300, 0x1f5ff, 1},
+		{0x1f600, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1},
+		{0x1f7e0, 0x1f7eb, 1},
+		{0x1f900, 0x1f9ff, 1},
+		{0x1fa70, 0x1faff, 1},
+	},
+}
+
+var regionalIndicator = &unicode.RangeTable{
+	R32: []unicode.Range32{
+		{0x1f1e6, 0x1f1ff, 1},
+	},
+}
+
+func initEmojiStates() {
+	s10 := addState(NewStateIdQuit(10, false))             // Emoji begin state
+	s11 := addState(NewStateIdQuit(11, false))             // Emoji zero width joiner state
+	s12 := addState(NewStateIdQuit(12, false))             // Regional indicator state
+	s13 := addState(NewStateNoDict(13, true, OutputEmoji)) // Emoji quit state
+
+	// 国旗由两个区域指示符组成
+	// s0 [RI] s12
+	s0.AddNextStateTable(regionalIndicator, s12.Id)
+
+	// s12 [RI] s10
+	s12.AddNextStateTable(regionalIndicator, s10.Id)
+
+	// s12 else s13
+	s12.AddElseState(s13.Id)
+
+	// s0 [emoji] s10
+	s0.AddNextStateTable(emojiBase, s10.Id)
+
+	// s10 [modifier] s10
+	s10.AddNextStateTable(emojiExtend, s10.Id)
+
+	// s10 [ZWJ] s11
+	s10.AddNextState('\u200d', s11.Id)
+
+	// s10 else s13
+	s10.AddElseState(s13.Id)
+
+	// s11 [emoji] s10
+	s11.AddNextStateTable(emojiBase, s10.Id)
+
+	// s11 else s13
+	s11.AddElseState(s13.Id)
+}
+
 func initOtherStates() {
-	s0.AddElseState(sother.Id)
+	s14 := addState(NewStateIdQuit(14, false)) // Symbol begin state
+
+	// s0 else s14
+	s0.AddElseState(s14.Id)
+
+	// 组合附加符号和前面的符号作为一个字素簇输出
+	// s14 [extend] s14
+	s14.AddNextStateTable(unicode.Mn, s14.Id)
+	s14.AddNextStateTable(unicode.Me, s14.Id)
+	s14.AddNextStateTable(graphemeExtend, s14.Id)
+
+	// s14 else s255
+	s14.AddElseState(sother.Id)
+}
+
+// 键帽表情由 [0-9#*]、变体选择符 FE0F 和组合键帽 20E3 组成，如 1️⃣、#️⃣，
+// 数字和 #* 开始的状态要先看后面是不是键帽，不是时仍然按数字和符号输出
+func initKeycapStates() {
+	s5, s6, s7, s10, s14 := states[5], states[6], states[7], states[10], states[14]
+	s20 := addState(NewStateIdQuit(20, false)) // Keycap digit state
+	s21 := addState(NewStateIdQuit(21, false)) // Keycap digit variation selector state
+	s22 := addState(NewStateIdQuit(22, false)) // Keycap symbol state
+	s23 := addState(NewStateIdQuit(23, false)) // Keycap symbol variation selector state
+
+	// s0 [0-9] s20
+	s0.AddNextStateFromTo('0', '9', s20.Id)
+
+	// s20 [0-9] s5
+	s20.AddNextStateFromTo('0', '9', s5.Id)
+	s20.AddNextStateFromTo('０', '９', s5.Id)
+
+	// s20 [\.] s6
+	s20.AddNextState('.', s6.Id)
+
+	// s20 [FE0F] s21
+	s20.AddNextState('\ufe0f', s21.Id)
+
+	// s20 [20E3] s10
+	s20.AddNextState('\u20e3', s10.Id)
+
+	// s20 else s7
+	s20.AddElseState(s7.Id)
+
+	// s21 [20E3] s10
+	s21.AddNextState('\u20e3', s10.Id)
+
+	// s21 else s7，变体选择符附着在数字上
+	s21.AddElseState(s7.Id)
+
+	// s0 [#*] s22
+	s0.AddNextStateArr([]rune{'#', '*'}, s22.Id)
+
+	// s22 [FE0F] s23
+	s22.AddNextState('\ufe0f', s23.Id)
+
+	// s22 [20E3] s10
+	s22.AddNextState('\u20e3', s10.Id)
+
+	// s23 [20E3] s10
+	s23.AddNextState('\u20e3', s10.Id)
+
+	// s22, s23 [extend] s14
+	for _, s := range []*State{s22, s23} {
+		s.AddNextStateTable(unicode.Mn, s14.Id)
+		s.AddNextStateTable(unicode.Me, s14.Id)
+		s.AddNextStateTable(graphemeExtend, s14.Id)
+
+		// else s255
+		s.AddElseState(sother.Id)
+	}
 }
 
 func addState(state *State) *State {
@@ -356,3 +651,8 @@ func (l *Lexical) Input(action rune, token int) int {
 
 	return Quit
 }
+
+// 跳过 index 之前的输入，只能在开始状态时调用
+func (l *Lexical) Skip(index int) {
+	l.beginIndex = index
+}
@@ -0,0 +1,64 @@
+package framework
+
+import (
+	"segment/dict"
+	"strings"
+	"testing"
+)
+
+// 和 Segment.getInitSegment 一样逐字输入，词之间用 / 分隔，表情符号后面带上 (emoji)
+func lex(text string) string {
+	runes := []rune(text)
+	lexical := NewLexical(runes)
+	words := []string{}
+	output := func() {
+		word := lexical.OutputToken.Word
+		if lexical.OutputToken.WordType == dict.TEmoji {
+			word += "(emoji)"
+		}
+		words = append(words, word)
+	}
+	for i := 0; i < len(runes); i++ {
+		switch lexical.Input(runes[i], i) {
+		case Quit:
+			output()
+		case ElseQuit:
+			output()
+			i--
+		}
+	}
+	switch lexical.Input(0, len(runes)) {
+	case Quit, ElseQuit:
+		output()
+	}
+	return strings.Join(words, "/")
+}
+
+func TestEmoji(t *testing.T) {
+	cases := map[string]string{
+		// 零宽连接符组成的序列
+		"\U0001f468\u200d\U0001f469\u200d\U0001f467好": "\U0001f468\u200d\U0001f469\u200d\U0001f467(emoji)/好",
+		"\U0001f469\u200d\U0001f4bb程序员":               "\U0001f469\u200d\U0001f4bb(emoji)/程序员",
+		// 国旗由两个区域指示符组成
+		"\U0001f1e8\U0001f1f3中国":                   "\U0001f1e8\U0001f1f3(emoji)/中国",
+		"\U0001f1fa\U0001f1f8\U0001f1e8\U0001f1f3": "\U0001f1fa\U0001f1f8(emoji)/\U0001f1e8\U0001f1f3(emoji)",
+		// 肤色修饰符
+		"\U0001f44d\U0001f3fb好": "\U0001f44d\U0001f3fb(emoji)/好",
+		// 键帽
+		"1\ufe0f\u20e3第一": "1\ufe0f\u20e3(emoji)/第一",
+		"3\ufe0f\u20e3":   "3\ufe0f\u20e3(emoji)",
+		"#\ufe0f\u20e3话题": "#\ufe0f\u20e3(emoji)/话题",
+		"*\u20e3":         "*\u20e3(emoji)",
+		// 不是键帽时仍然是数字和符号，变体选择符附着在前一个字符上
+		"12\ufe0f\u20e3": "12/\ufe0f\u20e3",
+		"9\ufe0f":        "9\ufe0f",
+		"1.5":            "1.5",
+		"2023年":          "2023/年",
+		"#话题#":           "#/话题/#",
+	}
+	for text, expected := range cases {
+		if got := lex(text); got != expected {
+			t.Errorf("%q: got %q, want %q", text, got, expected)
+		}
+	}
+}
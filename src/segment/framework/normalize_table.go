@@ -0,0 +1,300 @@
+// 由 unicode 字符数据库生成，对应 NFKC 中和中文文本相关的部分
+
+package framework
+
+// 兼容字符到单个字符的映射，两个字符串中的字符一一对应
+const (
+	widthFrom = "\u00a0ª²³µ¹º\u2000\u2001\u2002\u2003\u2004\u2005\u2006\u2007\u2008\u2009\u200a‑․\u202f\u205f⁰ⁱ" +
+		"⁴⁵⁶⁷⁸⁹⁺⁻⁼⁽⁾ⁿ₀₁₂₃₄₅₆₇₈₉₊₋" +
+		"₌₍₎ₐₑₒₓₔₕₖₗₘₙₚₛₜℂℇℊℋℌℍℎℏ" +
+		"ℐℑℒℓℕℙℚℛℜℝℤΩℨKÅℬℭℯℰℱℳℴℵℶ" +
+		"ℷℸℹℼℽℾℿ⅀ⅅⅆⅇⅈⅉⅠⅤⅩⅬⅭⅮⅯⅰⅴⅹⅼ" +
+		"ⅽⅾⅿ①②③④⑤⑥⑦⑧⑨ⒶⒷⒸⒹⒺⒻⒼⒽⒾⒿⓀⓁ" +
+		"ⓂⓃⓄⓅⓆⓇⓈⓉⓊⓋⓌⓍⓎⓏⓐⓑⓒⓓⓔⓕⓖⓗⓘⓙ" +
+		"ⓚⓛⓜⓝⓞⓟⓠⓡⓢⓣⓤⓥⓦⓧⓨⓩ⓪⺟⻳⼀⼁⼂⼃⼄" +
+		"⼅⼆⼇⼈⼉⼊⼋⼌⼍⼎⼏⼐⼑⼒⼓⼔⼕⼖⼗⼘⼙⼚⼛⼜" +
+		"⼝⼞⼟⼠⼡⼢⼣⼤⼥⼦⼧⼨⼩⼪⼫⼬⼭⼮⼯⼰⼱⼲⼳⼴" +
+		"⼵⼶⼷⼸⼹⼺⼻⼼⼽⼾⼿⽀⽁⽂⽃⽄⽅⽆⽇⽈⽉⽊⽋⽌" +
+		"⽍⽎⽏⽐⽑⽒⽓⽔⽕⽖⽗⽘⽙⽚⽛⽜⽝⽞⽟⽠⽡⽢⽣⽤" +
+		"⽥⽦⽧⽨⽩⽪⽫⽬⽭⽮⽯⽰⽱⽲⽳⽴⽵⽶⽷⽸⽹⽺⽻⽼" +
+		"⽽⽾⽿⾀⾁⾂⾃⾄⾅⾆⾇⾈⾉⾊⾋⾌⾍⾎⾏⾐⾑⾒⾓⾔" +
+		"⾕⾖⾗⾘⾙⾚⾛⾜⾝⾞⾟⾠⾡⾢⾣⾤⾥⾦⾧⾨⾩⾪⾫⾬" +
+		"⾭⾮⾯⾰⾱⾲⾳⾴⾵⾶⾷⾸⾹⾺⾻⾼⾽⾾⾿⿀⿁⿂⿃⿄" +
+		"⿅⿆⿇⿈⿉⿊⿋⿌⿍⿎⿏⿐⿑⿒⿓⿔⿕\u3000ㄱㄲㄳㄴㄵㄶ" +
+		"ㄷㄸㄹㄺㄻㄼㄽㄾㄿㅀㅁㅂㅃㅄㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ" +
+		"ㅏㅐㅑㅒㅓㅔㅕㅖㅗㅘㅙㅚㅛㅜㅝㅞㅟㅠㅡㅢㅣ\u3164ㅥㅦ" +
+		"ㅧㅨㅩㅪㅫㅬㅭㅮㅯㅰㅱㅲㅳㅴㅵㅶㅷㅸㅹㅺㅻㅼㅽㅾ" +
+		"ㅿㆀㆁㆂㆃㆄㆅㆆㆇㆈㆉㆊㆋㆌㆍㆎ㆒㆓㆔㆕㆖㆗㆘㆙" +
+		"㆚㆛㆜㆝㆞㆟㉄㉅㉆㉇㉠㉡㉢㉣㉤㉥㉦㉧㉨㉩㉪㉫㉬㉭" +
+		"㉮㉯㉰㉱㉲㉳㉴㉵㉶㉷㉸㉹㉺㉻㉾㊀㊁㊂㊃㊄㊅㊆㊇㊈" +
+		"㊉㊊㊋㊌㊍㊎㊏㊐㊑㊒㊓㊔㊕㊖㊗㊘㊙㊚㊛㊜㊝㊞㊟㊠" +
+		"㊡㊢㊣㊤㊥㊦㊧㊨㊩㊪㊫㊬㊭㊮㊯㊰㋐㋑㋒㋓㋔㋕㋖㋗" +
+		"㋘㋙㋚㋛㋜㋝㋞㋟㋠㋡㋢㋣㋤㋥㋦㋧㋨㋩㋪㋫㋬㋭㋮㋯" +
+		"㋰㋱㋲㋳㋴㋵㋶㋷㋸㋹㋺㋻㋼㋽㋾豈更車賈滑串句龜龜" +
+		"契金喇奈懶癩羅蘿螺裸邏樂洛烙珞落酪駱亂卵欄爛蘭鸞" +
+		"嵐濫藍襤拉臘蠟廊朗浪狼郎來冷勞擄櫓爐盧老蘆虜路露" +
+		"魯鷺碌祿綠菉錄鹿論壟弄籠聾牢磊賂雷壘屢樓淚漏累縷" +
+		"陋勒肋凜凌稜綾菱陵讀拏樂諾丹寧怒率異北磻便復不泌" +
+		"數索參塞省葉說殺辰沈拾若掠略亮兩凉梁糧良諒量勵呂" +
+		"女廬旅濾礪閭驪麗黎力曆歷轢年憐戀撚漣煉璉秊練聯輦" +
+		"蓮連鍊列劣咽烈裂說廉念捻殮簾獵令囹寧嶺怜玲瑩羚聆" +
+		"鈴零靈領例禮醴隸惡了僚寮尿料樂燎療蓼遼龍暈阮劉杻" +
+		"柳流溜琉留硫紐類六戮陸倫崙淪輪律慄栗率隆利吏履易" +
+		"李梨泥理痢罹裏裡里離匿溺吝燐璘藺隣鱗麟林淋臨立笠" +
+		"粒狀炙識什茶刺切度拓糖宅洞暴輻行降見廓兀嗀塚晴凞" +
+		"猪益礼神祥福靖精羽蘒諸逸都飯飼館鶴郞隷侮僧免勉勤" +
+		"卑喝嘆器塀墨層屮悔慨憎懲敏既暑梅海渚漢煮爫琢碑社" +
+		"祉祈祐祖祝禍禎穀突節練縉繁署者臭艹艹著褐視謁謹賓" +
+		"贈辶逸難響頻恵𤋮舘並况全侀充冀勇勺喝啕喙嗢塚墳奄" +
+		"奔婢嬨廒廙彩徭惘慎愈憎慠懲戴揄搜摒敖晴朗望杖歹殺" +
+		"流滛滋漢瀞煮瞧爵犯猪瑱甆画瘝瘟益盛直睊着磌窱節类" +
+		"絛練缾者荒華蝹襁覆視調諸請謁諾諭謹變贈輸遲醙鉶陼" +
+		"難靖韛響頋頻鬒龜𢡊𢡄𣏕㮝䀘䀹𥉉𥳐𧻓齃龎︐︑︒︓︔" +
+		"︕︖︗︘︱︲︳︴︵︶︷︸︹︺︻︼︽︾︿﹀﹁﹂﹃﹄" +
+		"﹇﹈﹍﹎﹏﹐﹑﹒﹔﹕﹖﹗﹘﹙﹚﹛﹜﹝﹞﹟﹠﹡﹢﹣" +
+		"﹤﹥﹦﹨﹩﹪﹫！＂＃＄％＆＇（）＊＋，－．／０１" +
+		"２３４５６７８９：；＜＝＞？＠ＡＢＣＤＥＦＧＨＩ" +
+		"ＪＫＬＭＮＯＰＱＲＳＴＵＶＷＸＹＺ［＼］＾＿｀ａ" +
+		"ｂｃｄｅｆｇｈｉｊｋｌｍｎｏｐｑｒｓｔｕｖｗｘｙ" +
+		"ｚ｛｜｝～｟｠｡｢｣､･ｦｧｨｩｪｫｬｭｮｯｰｱ" +
+		"ｲｳｴｵｶｷｸｹｺｻｼｽｾｿﾀﾁﾂﾃﾄﾅﾆﾇﾈﾉ" +
+		"ﾊﾋﾌﾍﾎﾏﾐﾑﾒﾓﾔﾕﾖﾗﾘﾙﾚﾛﾜﾝﾞﾟ\uffa0ﾡ" +
+		"ﾢﾣﾤﾥﾦﾧﾨﾩﾪﾫﾬﾭﾮﾯﾰﾱﾲﾳﾴﾵﾶﾷﾸﾹ" +
+		"ﾺﾻﾼﾽﾾￂￃￄￅￆￇￊￋￌￍￎￏￒￓￔￕￖￗￚ" +
+		"ￛￜ￠￡￢￤￥￦￨￩￪￫￬￭￮"
+	widthTo = "\u0020a23μ1o\u0020\u0020\u0020\u0020\u0020\u0020\u0020\u0020\u0020\u0020\u0020‐.\u0020\u00200i" +
+		"456789+−=()n0123456789+−" +
+		"=()aeoxəhklmnpstCƐgHHHhħ" +
+		"IILlNPQRRRZΩZKÅBCeEFMoאב" +
+		"גדiπγΓΠ∑DdeijIVXLCDMivxl" +
+		"cdm123456789ABCDEFGHIJKL" +
+		"MNOPQRSTUVWXYZabcdefghij" +
+		"klmnopqrstuvwxyz0母龟一丨丶丿乙" +
+		"亅二亠人儿入八冂冖冫几凵刀力勹匕匚匸十卜卩厂厶又" +
+		"口囗土士夂夊夕大女子宀寸小尢尸屮山巛工己巾干幺广" +
+		"廴廾弋弓彐彡彳心戈戶手支攴文斗斤方无日曰月木欠止" +
+		"歹殳毋比毛氏气水火爪父爻爿片牙牛犬玄玉瓜瓦甘生用" +
+		"田疋疒癶白皮皿目矛矢石示禸禾穴立竹米糸缶网羊羽老" +
+		"而耒耳聿肉臣自至臼舌舛舟艮色艸虍虫血行衣襾見角言" +
+		"谷豆豕豸貝赤走足身車辛辰辵邑酉釆里金長門阜隶隹雨" +
+		"靑非面革韋韭音頁風飛食首香馬骨高髟鬥鬯鬲鬼魚鳥鹵" +
+		"鹿麥麻黃黍黑黹黽鼎鼓鼠鼻齊齒龍龜龠\u0020ᄀᄁᆪᄂᆬᆭ" +
+		"ᄃᄄᄅᆰᆱᆲᆳᆴᆵᄚᄆᄇᄈᄡᄉᄊᄋᄌᄍᄎᄏᄐᄑᄒ" +
+		"ᅡᅢᅣᅤᅥᅦᅧᅨᅩᅪᅫᅬᅭᅮᅯᅰᅱᅲᅳᅴᅵᅠᄔᄕ" +
+		"ᇇᇈᇌᇎᇓᇗᇙᄜᇝᇟᄝᄞᄠᄢᄣᄧᄩᄫᄬᄭᄮᄯᄲᄶ" +
+		"ᅀᅇᅌᇱᇲᅗᅘᅙᆄᆅᆈᆑᆒᆔᆞᆡ一二三四上中下甲" +
+		"乙丙丁天地人問幼文箏ᄀᄂᄃᄅᄆᄇᄉᄋᄌᄎᄏᄐᄑᄒ" +
+		"가나다라마바사아자차카타파하우一二三四五六七八九" +
+		"十月火水木金土日株有社名特財祝労秘男女適優印注項" +
+		"休写正上中下左右医宗学監企資協夜アイウエオカキク" +
+		"ケコサシスセソタチツテトナニヌネノハヒフヘホマミ" +
+		"ムメモヤユヨラリルレロワヰヱヲ豈更車賈滑串句龜龜" +
+		"契金喇奈懶癩羅蘿螺裸邏樂洛烙珞落酪駱亂卵欄爛蘭鸞" +
+		"嵐濫藍襤拉臘蠟廊朗浪狼郎來冷勞擄櫓爐盧老蘆虜路露" +
+		"魯鷺碌祿綠菉錄鹿論壟弄籠聾牢磊賂雷壘屢樓淚漏累縷" +
+		"陋勒肋凜凌稜綾菱陵讀拏樂諾丹寧怒率異北磻便復不泌" +
+		"數索參塞省葉說殺辰沈拾若掠略亮兩凉梁糧良諒量勵呂" +
+		"女廬旅濾礪閭驪麗黎力曆歷轢年憐戀撚漣煉璉秊練聯輦" +
+		"蓮連鍊列劣咽烈裂說廉念捻殮簾獵令囹寧嶺怜玲瑩羚聆" +
+		"鈴零靈領例禮醴隸惡了僚寮尿料樂燎療蓼遼龍暈阮劉杻" +
+		"柳流溜琉留硫紐類六戮陸倫崙淪輪律慄栗率隆利吏履易" +
+		"李梨泥理痢罹裏裡里離匿溺吝燐璘藺隣鱗麟林淋臨立笠" +
+		"粒狀炙識什茶刺切度拓糖宅洞暴輻行降見廓兀嗀塚晴凞" +
+		"猪益礼神祥福靖精羽蘒諸逸都飯飼館鶴郞隷侮僧免勉勤" +
+		"卑喝嘆器塀墨層屮悔慨憎懲敏既暑梅海渚漢煮爫琢碑社" +
+		"祉祈祐祖祝禍禎穀突節練縉繁署者臭艹艹著褐視謁謹賓" +
+		"贈辶逸難響頻恵𤋮舘並况全侀充冀勇勺喝啕喙嗢塚墳奄" +
+		"奔婢嬨廒廙彩徭惘慎愈憎慠懲戴揄搜摒敖晴朗望杖歹殺" +
+		"流滛滋漢瀞煮瞧爵犯猪瑱甆画瘝瘟益盛直睊着磌窱節类" +
+		"絛練缾者荒華蝹襁覆視調諸請謁諾諭謹變贈輸遲醙鉶陼" +
+		"難靖韛響頋頻鬒龜𢡊𢡄𣏕㮝䀘䀹𥉉𥳐𧻓齃龎,、。:;" +
+		"!?〖〗—–__(){}〔〕【】《》〈〉「」『』" +
+		"[]___,、.;:?!—(){}〔〕#&*+-" +
+		"<>=\\$%@!\"#$%&'()*+,-./01" +
+		"23456789:;<=>?@ABCDEFGHI" +
+		"JKLMNOPQRSTUVWXYZ[\\]^_`a" +
+		"bcdefghijklmnopqrstuvwxy" +
+		"z{|}~⦅⦆。「」、・ヲァィゥェォャュョッーア" +
+		"イウエオカキクケコサシスセソタチツテトナニヌネノ" +
+		"ハヒフヘホマミムメモヤユヨラリルレロワン\u3099\u309aᅠᄀ" +
+		"ᄁᆪᄂᆬᆭᄃᄄᄅᆰᆱᆲᆳᆴᆵᄚᄆᄇᄈᄡᄉᄊᄋᄌᄍ" +
+		"ᄎᄏᄐᄑ하ᅢᅣᅤᅥᅦᅧᅨᅩᅪᅫᅬᅭᅮᅯᅰᅱᅲᅳ" +
+		"ᅴᅵ¢£¬¦¥₩│←↑→↓■○"
+)
+
+// 兼容字符到多个字符的映射，每项的第一个字符是兼容字符，后面是映射结果，项之间以 \x00 分割
+const widthMulti = "¼1⁄4\x00½1⁄2\x00¾3⁄4\x00‥..\x00…...\x00″′′\x00‴′′′\x00‶‵‵\x00" +
+	"‷‵‵‵\x00‼!!\x00⁇??\x00⁈?!\x00⁉!?\x00⁗′′′′\x00₨Rs\x00℀a/c\x00" +
+	"℁a/s\x00℃°C\x00℅c/o\x00℆c/u\x00℉°F\x00№No\x00℠SM\x00℡TEL\x00" +
+	"™TM\x00℻FAX\x00⅐1⁄7\x00⅑1⁄9\x00⅒1⁄10\x00⅓1⁄3\x00⅔2⁄3\x00⅕1⁄5\x00" +
+	"⅖2⁄5\x00⅗3⁄5\x00⅘4⁄5\x00⅙1⁄6\x00⅚5⁄6\x00⅛1⁄8\x00⅜3⁄8\x00⅝5⁄8\x00" +
+	"⅞7⁄8\x00⅟1⁄\x00ⅡII\x00ⅢIII\x00ⅣIV\x00ⅥVI\x00ⅦVII\x00ⅧVIII\x00" +
+	"ⅨIX\x00ⅪXI\x00ⅫXII\x00ⅱii\x00ⅲiii\x00ⅳiv\x00ⅵvi\x00ⅶvii\x00" +
+	"ⅷviii\x00ⅸix\x00ⅺxi\x00ⅻxii\x00↉0⁄3\x00⑩10\x00⑪11\x00⑫12\x00" +
+	"⑬13\x00⑭14\x00⑮15\x00⑯16\x00⑰17\x00⑱18\x00⑲19\x00⑳20\x00" +
+	"⑴(1)\x00⑵(2)\x00⑶(3)\x00⑷(4)\x00⑸(5)\x00⑹(6)\x00⑺(7)\x00⑻(8)\x00" +
+	"⑼(9)\x00⑽(10)\x00⑾(11)\x00⑿(12)\x00⒀(13)\x00⒁(14)\x00⒂(15)\x00⒃(16)\x00" +
+	"⒄(17)\x00⒅(18)\x00⒆(19)\x00⒇(20)\x00⒈1.\x00⒉2.\x00⒊3.\x00⒋4.\x00" +
+	"⒌5.\x00⒍6.\x00⒎7.\x00⒏8.\x00⒐9.\x00⒑10.\x00⒒11.\x00⒓12.\x00" +
+	"⒔13.\x00⒕14.\x00⒖15.\x00⒗16.\x00⒘17.\x00⒙18.\x00⒚19.\x00⒛20.\x00" +
+	"⒜(a)\x00⒝(b)\x00⒞(c)\x00⒟(d)\x00⒠(e)\x00⒡(f)\x00⒢(g)\x00⒣(h)\x00" +
+	"⒤(i)\x00⒥(j)\x00⒦(k)\x00⒧(l)\x00⒨(m)\x00⒩(n)\x00⒪(o)\x00⒫(p)\x00" +
+	"⒬(q)\x00⒭(r)\x00⒮(s)\x00⒯(t)\x00⒰(u)\x00⒱(v)\x00⒲(w)\x00⒳(x)\x00" +
+	"⒴(y)\x00⒵(z)\x00ゟより\x00ヿコト\x00㈀(ᄀ)\x00㈁(ᄂ)\x00㈂(ᄃ)\x00㈃(ᄅ)\x00" +
+	"㈄(ᄆ)\x00㈅(ᄇ)\x00㈆(ᄉ)\x00㈇(ᄋ)\x00㈈(ᄌ)\x00㈉(ᄎ)\x00㈊(ᄏ)\x00㈋(ᄐ)\x00" +
+	"㈌(ᄑ)\x00㈍(ᄒ)\x00㈎(가)\x00㈏(나)\x00㈐(다)\x00㈑(라)\x00㈒(마)\x00㈓(바)\x00" +
+	"㈔(사)\x00㈕(아)\x00㈖(자)\x00㈗(차)\x00㈘(카)\x00㈙(타)\x00㈚(파)\x00㈛(하)\x00" +
+	"㈜(주)\x00㈝(오전)\x00㈞(오후)\x00㈠(一)\x00㈡(二)\x00㈢(三)\x00㈣(四)\x00㈤(五)\x00" +
+	"㈥(六)\x00㈦(七)\x00㈧(八)\x00㈨(九)\x00㈩(十)\x00㈪(月)\x00㈫(火)\x00㈬(水)\x00" +
+	"㈭(木)\x00㈮(金)\x00㈯(土)\x00㈰(日)\x00㈱(株)\x00㈲(有)\x00㈳(社)\x00㈴(名)\x00" +
+	"㈵(特)\x00㈶(財)\x00㈷(祝)\x00㈸(労)\x00㈹(代)\x00㈺(呼)\x00㈻(学)\x00㈼(監)\x00" +
+	"㈽(企)\x00㈾(資)\x00㈿(協)\x00㉀(祭)\x00㉁(休)\x00㉂(自)\x00㉃(至)\x00㉐PTE\x00" +
+	"㉑21\x00㉒22\x00㉓23\x00㉔24\x00㉕25\x00㉖26\x00㉗27\x00㉘28\x00" +
+	"㉙29\x00㉚30\x00㉛31\x00㉜32\x00㉝33\x00㉞34\x00㉟35\x00㉼참고\x00" +
+	"㉽주의\x00㊱36\x00㊲37\x00㊳38\x00㊴39\x00㊵40\x00㊶41\x00㊷42\x00" +
+	"㊸43\x00㊹44\x00㊺45\x00㊻46\x00㊼47\x00㊽48\x00㊾49\x00㊿50\x00" +
+	"㋀1月\x00㋁2月\x00㋂3月\x00㋃4月\x00㋄5月\x00㋅6月\x00㋆7月\x00㋇8月\x00" +
+	"㋈9月\x00㋉10月\x00㋊11月\x00㋋12月\x00㋌Hg\x00㋍erg\x00㋎eV\x00㋏LTD\x00" +
+	"㋿令和\x00㌀アパート\x00㌁アルファ\x00㌂アンペア\x00㌃アール\x00㌄イニング\x00㌅インチ\x00㌆ウォン\x00" +
+	"㌇エスクード\x00㌈エーカー\x00㌉オンス\x00㌊オーム\x00㌋カイリ\x00㌌カラット\x00㌍カロリー\x00㌎ガロン\x00" +
+	"㌏ガンマ\x00㌐ギガ\x00㌑ギニー\x00㌒キュリー\x00㌓ギルダー\x00㌔キロ\x00㌕キログラム\x00㌖キロメートル\x00" +
+	"㌗キロワット\x00㌘グラム\x00㌙グラムトン\x00㌚クルゼイロ\x00㌛クローネ\x00㌜ケース\x00㌝コルナ\x00㌞コーポ\x00" +
+	"㌟サイクル\x00㌠サンチーム\x00㌡シリング\x00㌢センチ\x00㌣セント\x00㌤ダース\x00㌥デシ\x00㌦ドル\x00" +
+	"㌧トン\x00㌨ナノ\x00㌩ノット\x00㌪ハイツ\x00㌫パーセント\x00㌬パーツ\x00㌭バーレル\x00㌮ピアストル\x00" +
+	"㌯ピクル\x00㌰ピコ\x00㌱ビル\x00㌲ファラッド\x00㌳フィート\x00㌴ブッシェル\x00㌵フラン\x00㌶ヘクタール\x00" +
+	"㌷ペソ\x00㌸ペニヒ\x00㌹ヘルツ\x00㌺ペンス\x00㌻ページ\x00㌼ベータ\x00㌽ポイント\x00㌾ボルト\x00" +
+	"㌿ホン\x00㍀ポンド\x00㍁ホール\x00㍂ホーン\x00㍃マイクロ\x00㍄マイル\x00㍅マッハ\x00㍆マルク\x00" +
+	"㍇マンション\x00㍈ミクロン\x00㍉ミリ\x00㍊ミリバール\x00㍋メガ\x00㍌メガトン\x00㍍メートル\x00㍎ヤード\x00" +
+	"㍏ヤール\x00㍐ユアン\x00㍑リットル\x00㍒リラ\x00㍓ルピー\x00㍔ルーブル\x00㍕レム\x00㍖レントゲン\x00" +
+	"㍗ワット\x00㍘0点\x00㍙1点\x00㍚2点\x00㍛3点\x00㍜4点\x00㍝5点\x00㍞6点\x00" +
+	"㍟7点\x00㍠8点\x00㍡9点\x00㍢10点\x00㍣11点\x00㍤12点\x00㍥13点\x00㍦14点\x00" +
+	"㍧15点\x00㍨16点\x00㍩17点\x00㍪18点\x00㍫19点\x00㍬20点\x00㍭21点\x00㍮22点\x00" +
+	"㍯23点\x00㍰24点\x00㍱hPa\x00㍲da\x00㍳AU\x00㍴bar\x00㍵oV\x00㍶pc\x00" +
+	"㍷dm\x00㍸dm2\x00㍹dm3\x00㍺IU\x00㍻平成\x00㍼昭和\x00㍽大正\x00㍾明治\x00" +
+	"㍿株式会社\x00㎀pA\x00㎁nA\x00㎂μA\x00㎃mA\x00㎄kA\x00㎅KB\x00㎆MB\x00" +
+	"㎇GB\x00㎈cal\x00㎉kcal\x00㎊pF\x00㎋nF\x00㎌μF\x00㎍μg\x00㎎mg\x00" +
+	"㎏kg\x00㎐Hz\x00㎑kHz\x00㎒MHz\x00㎓GHz\x00㎔THz\x00㎕μl\x00㎖ml\x00" +
+	"㎗dl\x00㎘kl\x00㎙fm\x00㎚nm\x00㎛μm\x00㎜mm\x00㎝cm\x00㎞km\x00" +
+	"㎟mm2\x00㎠cm2\x00㎡m2\x00㎢km2\x00㎣mm3\x00㎤cm3\x00㎥m3\x00㎦km3\x00" +
+	"㎧m∕s\x00㎨m∕s2\x00㎩Pa\x00㎪kPa\x00㎫MPa\x00㎬GPa\x00㎭rad\x00㎮rad∕s\x00" +
+	"㎯rad∕s2\x00㎰ps\x00㎱ns\x00㎲μs\x00㎳ms\x00㎴pV\x00㎵nV\x00㎶μV\x00" +
+	"㎷mV\x00㎸kV\x00㎹MV\x00㎺pW\x00㎻nW\x00㎼μW\x00㎽mW\x00㎾kW\x00" +
+	"㎿MW\x00㏀kΩ\x00㏁MΩ\x00㏂a.m.\x00㏃Bq\x00㏄cc\x00㏅cd\x00㏆C∕kg\x00" +
+	"㏇Co.\x00㏈dB\x00㏉Gy\x00㏊ha\x00㏋HP\x00㏌in\x00㏍KK\x00㏎KM\x00" +
+	"㏏kt\x00㏐lm\x00㏑ln\x00㏒log\x00㏓lx\x00㏔mb\x00㏕mil\x00㏖mol\x00" +
+	"㏗PH\x00㏘p.m.\x00㏙PPM\x00㏚PR\x00㏛sr\x00㏜Sv\x00㏝Wb\x00㏞V∕m\x00" +
+	"㏟A∕m\x00㏠1日\x00㏡2日\x00㏢3日\x00㏣4日\x00㏤5日\x00㏥6日\x00㏦7日\x00" +
+	"㏧8日\x00㏨9日\x00㏩10日\x00㏪11日\x00㏫12日\x00㏬13日\x00㏭14日\x00㏮15日\x00" +
+	"㏯16日\x00㏰17日\x00㏱18日\x00㏲19日\x00㏳20日\x00㏴21日\x00㏵22日\x00㏶23日\x00" +
+	"㏷24日\x00㏸25日\x00㏹26日\x00㏺27日\x00㏻28日\x00㏼29日\x00㏽30日\x00㏾31日\x00" +
+	"㏿gal\x00ﬀff\x00ﬁfi\x00ﬂfl\x00ﬃffi\x00ﬄffl\x00ﬅst\x00ﬆst\x00" +
+	"︙...\x00︰..\x00"
+
+// 基本字符 + 组合附加符 => 预组合字符，三个字符串中的字符一一对应
+const (
+	composeBase = "AAAAAACEEEEIIIINOOOOOUUU" +
+		"UYaaaaaaceeeeiiiinooooou" +
+		"uuuyyAaAaAaCcCcCcCcDdEeE" +
+		"eEeEeEeGgGgGgGgHhIiIiIiI" +
+		"iIJjKkLlLlLlNnNnNnOoOoOo" +
+		"RrRrRrSsSsSsSsTtTtUuUuUu" +
+		"UuUuUuWwYyYZzZzZzOoUuAaI" +
+		"iOoUuÜüÜüÜüÜüÄäȦȧÆæGgKkO" +
+		"oǪǫƷʒjGgNnÅåÆæØøAaAaEeEe" +
+		"IiIiOoOoRrRrUuUuSsTtHhAa" +
+		"EeÖöÕõOoȮȯYyAaBbBbBbÇçDd" +
+		"DdDdDdDdĒēĒēEeEeȨȩFfGgHh" +
+		"HhHhHhHhIiÏïKkKkKkLlḶḷLl" +
+		"LlMmMmMmNnNnNnNnÕõÕõŌōŌō" +
+		"PpPpRrRrṚṛRrSsSsŚśŠšṢṣTt" +
+		"TtTtTtUuUuUuŨũŪūVvVvWwWw" +
+		"WwWwWwXxXxYyZzZzZzhtwyſA" +
+		"aAaÂâÂâÂâÂâẠạĂăĂăĂăĂăẠạE" +
+		"eEeEeÊêÊêÊêÊêẸẹIiIiOoOoÔ" +
+		"ôÔôÔôÔôỌọƠơƠơƠơƠơƠơUuUuƯ" +
+		"ưƯưƯưƯưƯưYyYyYyYyααἀἁἀἁἀ" +
+		"ἁΑΑἈἉἈἉἈἉεεἐἑἐἑΕΕἘἙἘἙηηἠ" +
+		"ἡἠἡἠἡΗΗἨἩἨἩἨἩιιἰἱἰἱἰἱΙΙἸ" +
+		"ἹἸἹἸἹοοὀὁὀὁΟΟὈὉὈὉυυὐὑὐὑὐ" +
+		"ὑΥὙὙὙωωὠὡὠὡὠὡΩΩὨὩὨὩὨὩαεη" +
+		"ιουωἀἁἂἃἄἅἆἇἈἉἊἋἌἍἎἏἠἡἢἣ" +
+		"ἤἥἦἧἨἩἪἫἬἭἮἯὠὡὢὣὤὥὦὧὨὩὪὫ" +
+		"ὬὭὮὯααὰαάαᾶΑΑΑΑ¨ὴηήηῆΕΗΗ" +
+		"᾿᾿᾿ιιϊιϊΙΙΙ῾῾῾υυϋρρυϋΥΥΥ" +
+		"Ρ¨ὼωώωῶΟΩΩ¨ΑΕΗΙΟΥΩϊΙΥαεη" +
+		"ιϋιυουωϒϒЕЕГІКИУИиеегіки" +
+		"уѴѵЖжАаАаЕеӘәЖжЗзИиИиОоӨ" +
+		"өЭэУуУуУуЧчЫыかきくけこさしすせそた" +
+		"ちつてとははひひふふへへほほうゝカキクケコサシス" +
+		"セソタチツテトハハヒヒフフヘヘホホウワヰヱヲヽ"
+	composeMark = "\u0300\u0301\u0302\u0303\u0308\u030a\u0327\u0300\u0301\u0302\u0308\u0300\u0301\u0302\u0308\u0303\u0300\u0301\u0302\u0303\u0308\u0300\u0301\u0302" +
+		"\u0308\u0301\u0300\u0301\u0302\u0303\u0308\u030a\u0327\u0300\u0301\u0302\u0308\u0300\u0301\u0302\u0308\u0303\u0300\u0301\u0302\u0303\u0308\u0300" +
+		"\u0301\u0302\u0308\u0301\u0308\u0304\u0304\u0306\u0306\u0328\u0328\u0301\u0301\u0302\u0302\u0307\u0307\u030c\u030c\u030c\u030c\u0304\u0304\u0306" +
+		"\u0306\u0307\u0307\u0328\u0328\u030c\u030c\u0302\u0302\u0306\u0306\u0307\u0307\u0327\u0327\u0302\u0302\u0303\u0303\u0304\u0304\u0306\u0306\u0328" +
+		"\u0328\u0307\u0302\u0302\u0327\u0327\u0301\u0301\u0327\u0327\u030c\u030c\u0301\u0301\u0327\u0327\u030c\u030c\u0304\u0304\u0306\u0306\u030b\u030b" +
+		"\u0301\u0301\u0327\u0327\u030c\u030c\u0301\u0301\u0302\u0302\u0327\u0327\u030c\u030c\u0327\u0327\u030c\u030c\u0303\u0303\u0304\u0304\u0306\u0306" +
+		"\u030a\u030a\u030b\u030b\u0328\u0328\u0302\u0302\u0302\u0302\u0308\u0301\u0301\u0307\u0307\u030c\u030c\u031b\u031b\u031b\u031b\u030c\u030c\u030c" +
+		"\u030c\u030c\u030c\u030c\u030c\u0304\u0304\u0301\u0301\u030c\u030c\u0300\u0300\u0304\u0304\u0304\u0304\u0304\u0304\u030c\u030c\u030c\u030c\u0328" +
+		"\u0328\u0304\u0304\u030c\u030c\u030c\u0301\u0301\u0300\u0300\u0301\u0301\u0301\u0301\u0301\u0301\u030f\u030f\u0311\u0311\u030f\u030f\u0311\u0311" +
+		"\u030f\u030f\u0311\u0311\u030f\u030f\u0311\u0311\u030f\u030f\u0311\u0311\u030f\u030f\u0311\u0311\u0326\u0326\u0326\u0326\u030c\u030c\u0307\u0307" +
+		"\u0327\u0327\u0304\u0304\u0304\u0304\u0307\u0307\u0304\u0304\u0304\u0304\u0325\u0325\u0307\u0307\u0323\u0323\u0331\u0331\u0301\u0301\u0307\u0307" +
+		"\u0323\u0323\u0331\u0331\u0327\u0327\u032d\u032d\u0300\u0300\u0301\u0301\u032d\u032d\u0330\u0330\u0306\u0306\u0307\u0307\u0304\u0304\u0307\u0307" +
+		"\u0323\u0323\u0308\u0308\u0327\u0327\u032e\u032e\u0330\u0330\u0301\u0301\u0301\u0301\u0323\u0323\u0331\u0331\u0323\u0323\u0304\u0304\u0331\u0331" +
+		"\u032d\u032d\u0301\u0301\u0307\u0307\u0323\u0323\u0307\u0307\u0323\u0323\u0331\u0331\u032d\u032d\u0301\u0301\u0308\u0308\u0300\u0300\u0301\u0301" +
+		"\u0301\u0301\u0307\u0307\u0307\u0307\u0323\u0323\u0304\u0304\u0331\u0331\u0307\u0307\u0323\u0323\u0307\u0307\u0307\u0307\u0307\u0307\u0307\u0307" +
+		"\u0323\u0323\u0331\u0331\u032d\u032d\u0324\u0324\u0330\u0330\u032d\u032d\u0301\u0301\u0308\u0308\u0303\u0303\u0323\u0323\u0300\u0300\u0301\u0301" +
+		"\u0308\u0308\u0307\u0307\u0323\u0323\u0307\u0307\u0308\u0308\u0307\u0307\u0302\u0302\u0323\u0323\u0331\u0331\u0331\u0308\u030a\u030a\u0307\u0323" +
+		"\u0323\u0309\u0309\u0301\u0301\u0300\u0300\u0309\u0309\u0303\u0303\u0302\u0302\u0301\u0301\u0300\u0300\u0309\u0309\u0303\u0303\u0306\u0306\u0323" +
+		"\u0323\u0309\u0309\u0303\u0303\u0301\u0301\u0300\u0300\u0309\u0309\u0303\u0303\u0302\u0302\u0309\u0309\u0323\u0323\u0323\u0323\u0309\u0309\u0301" +
+		"\u0301\u0300\u0300\u0309\u0309\u0303\u0303\u0302\u0302\u0301\u0301\u0300\u0300\u0309\u0309\u0303\u0303\u0323\u0323\u0323\u0323\u0309\u0309\u0301" +
+		"\u0301\u0300\u0300\u0309\u0309\u0303\u0303\u0323\u0323\u0300\u0300\u0323\u0323\u0309\u0309\u0303\u0303\u0313\u0314\u0300\u0300\u0301\u0301\u0342" +
+		"\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0342\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0313\u0314\u0300\u0300\u0301\u0301\u0313\u0314\u0300" +
+		"\u0300\u0301\u0301\u0342\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0342\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0342\u0342\u0313\u0314\u0300" +
+		"\u0300\u0301\u0301\u0342\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0313\u0314\u0300\u0300\u0301\u0301\u0313\u0314\u0300\u0300\u0301\u0301\u0342" +
+		"\u0342\u0314\u0300\u0301\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0342\u0342\u0313\u0314\u0300\u0300\u0301\u0301\u0342\u0342\u0300\u0300\u0300" +
+		"\u0300\u0300\u0300\u0300\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345" +
+		"\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345\u0345" +
+		"\u0345\u0345\u0345\u0345\u0306\u0304\u0345\u0345\u0345\u0342\u0345\u0306\u0304\u0300\u0345\u0342\u0345\u0345\u0345\u0342\u0345\u0300\u0300\u0345" +
+		"\u0300\u0301\u0342\u0306\u0304\u0300\u0342\u0342\u0306\u0304\u0300\u0300\u0301\u0342\u0306\u0304\u0300\u0313\u0314\u0342\u0342\u0306\u0304\u0300" +
+		"\u0314\u0300\u0345\u0345\u0345\u0342\u0345\u0300\u0300\u0345\u0301\u0301\u0301\u0301\u0301\u0301\u0301\u0301\u0301\u0308\u0308\u0301\u0301\u0301" +
+		"\u0301\u0301\u0308\u0308\u0301\u0301\u0301\u0301\u0308\u0300\u0308\u0301\u0308\u0301\u0300\u0306\u0306\u0306\u0300\u0308\u0301\u0308\u0301\u0300" +
+		"\u0306\u030f\u030f\u0306\u0306\u0306\u0306\u0308\u0308\u0306\u0306\u0308\u0308\u0308\u0308\u0308\u0308\u0304\u0304\u0308\u0308\u0308\u0308\u0308" +
+		"\u0308\u0308\u0308\u0304\u0304\u0308\u0308\u030b\u030b\u0308\u0308\u0308\u0308\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099" +
+		"\u3099\u3099\u3099\u3099\u3099\u309a\u3099\u309a\u3099\u309a\u3099\u309a\u3099\u309a\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099" +
+		"\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u3099\u309a\u3099\u309a\u3099\u309a\u3099\u309a\u3099\u309a\u3099\u3099\u3099\u3099\u3099\u3099"
+	composeTo = "ÀÁÂÃÄÅÇÈÉÊËÌÍÎÏÑÒÓÔÕÖÙÚÛ" +
+		"ÜÝàáâãäåçèéêëìíîïñòóôõöù" +
+		"úûüýÿĀāĂăĄąĆćĈĉĊċČčĎďĒēĔ" +
+		"ĕĖėĘęĚěĜĝĞğĠġĢģĤĥĨĩĪīĬĭĮ" +
+		"įİĴĵĶķĹĺĻļĽľŃńŅņŇňŌōŎŏŐő" +
+		"ŔŕŖŗŘřŚśŜŝŞşŠšŢţŤťŨũŪūŬŭ" +
+		"ŮůŰűŲųŴŵŶŷŸŹźŻżŽžƠơƯưǍǎǏ" +
+		"ǐǑǒǓǔǕǖǗǘǙǚǛǜǞǟǠǡǢǣǦǧǨǩǪ" +
+		"ǫǬǭǮǯǰǴǵǸǹǺǻǼǽǾǿȀȁȂȃȄȅȆȇ" +
+		"ȈȉȊȋȌȍȎȏȐȑȒȓȔȕȖȗȘșȚțȞȟȦȧ" +
+		"ȨȩȪȫȬȭȮȯȰȱȲȳḀḁḂḃḄḅḆḇḈḉḊḋ" +
+		"ḌḍḎḏḐḑḒḓḔḕḖḗḘḙḚḛḜḝḞḟḠḡḢḣ" +
+		"ḤḥḦḧḨḩḪḫḬḭḮḯḰḱḲḳḴḵḶḷḸḹḺḻ" +
+		"ḼḽḾḿṀṁṂṃṄṅṆṇṈṉṊṋṌṍṎṏṐṑṒṓ" +
+		"ṔṕṖṗṘṙṚṛṜṝṞṟṠṡṢṣṤṥṦṧṨṩṪṫ" +
+		"ṬṭṮṯṰṱṲṳṴṵṶṷṸṹṺṻṼṽṾṿẀẁẂẃ" +
+		"ẄẅẆẇẈẉẊẋẌẍẎẏẐẑẒẓẔẕẖẗẘẙẛẠ" +
+		"ạẢảẤấẦầẨẩẪẫẬậẮắẰằẲẳẴẵẶặẸ" +
+		"ẹẺẻẼẽẾếỀềỂểỄễỆệỈỉỊịỌọỎỏỐ" +
+		"ốỒồỔổỖỗỘộỚớỜờỞởỠỡỢợỤụỦủỨ" +
+		"ứỪừỬửỮữỰựỲỳỴỵỶỷỸỹἀἁἂἃἄἅἆ" +
+		"ἇἈἉἊἋἌἍἎἏἐἑἒἓἔἕἘἙἚἛἜἝἠἡἢ" +
+		"ἣἤἥἦἧἨἩἪἫἬἭἮἯἰἱἲἳἴἵἶἷἸἹἺ" +
+		"ἻἼἽἾἿὀὁὂὃὄὅὈὉὊὋὌὍὐὑὒὓὔὕὖ" +
+		"ὗὙὛὝὟὠὡὢὣὤὥὦὧὨὩὪὫὬὭὮὯὰὲὴ" +
+		"ὶὸὺὼᾀᾁᾂᾃᾄᾅᾆᾇᾈᾉᾊᾋᾌᾍᾎᾏᾐᾑᾒᾓ" +
+		"ᾔᾕᾖᾗᾘᾙᾚᾛᾜᾝᾞᾟᾠᾡᾢᾣᾤᾥᾦᾧᾨᾩᾪᾫ" +
+		"ᾬᾭᾮᾯᾰᾱᾲᾳᾴᾶᾷᾸᾹᾺᾼ῁ῂῃῄῆῇῈῊῌ" +
+		"῍῎῏ῐῑῒῖῗῘῙῚ῝῞῟ῠῡῢῤῥῦῧῨῩῪ" +
+		"Ῥ῭ῲῳῴῶῷῸῺῼ΅ΆΈΉΊΌΎΏΐΪΫάέή" +
+		"ίΰϊϋόύώϓϔЀЁЃЇЌЍЎЙйѐёѓїќѝ" +
+		"ўѶѷӁӂӐӑӒӓӖӗӚӛӜӝӞӟӢӣӤӥӦӧӪ" +
+		"ӫӬӭӮӯӰӱӲӳӴӵӸӹがぎぐげござじずぜぞだ" +
+		"ぢづでどばぱびぴぶぷべぺぼぽゔゞガギグゲゴザジズ" +
+		"ゼゾダヂヅデドバパビピブプベペボポヴヷヸヹヺヾ"
+)
@@ -0,0 +1,132 @@
+package framework
+
+import (
+	"segment/utils"
+	"strings"
+	"unicode"
+)
+
+// Normalize Function
+const (
+	NormalizeWidth       = 1 // 全角半角及兼容字符归一化，相当于 NFKC
+	NormalizePunctuation = 2 // 标点符号归一化
+	NormalizeVariant     = 4 // 异体字归一化
+	NormalizeCase        = 8 // 大小写折叠
+)
+
+// 中文标点和各种引号、破折号、空白统一成 ASCII 形式，零宽字符直接去掉
+var punctuationDict = map[rune]string{
+	'，': ",", '。': ".", '、': ",", '；': ";", '：': ":", '？': "?", '！': "!",
+	'（': "(", '）': ")", '［': "[", '］': "]", '｛': "{", '｝': "}",
+	'【': "[", '】': "]", '〔': "[", '〕': "]", '〖': "[", '〗': "]",
+	'《': "<", '》': ">", '〈': "<", '〉': ">",
+	'“': "\"", '”': "\"", '„': "\"", '‟': "\"", '〝': "\"", '〞': "\"", '＂': "\"",
+	'「': "\"", '」': "\"", '『': "\"", '』': "\"",
+	'‘': "'", '’': "'", '‚': "'", '‛': "'", '＇': "'",
+	'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-", '－': "-",
+	'～': "~", '〜': "~",
+	'…': "...", '‥': "..",
+	'・': "·", '•': "·", '‧': "·", '∙': "·", '⋅': "·",
+	'\u3000': " ", '\u00a0': " ", '\u2002': " ", '\u2003': " ", '\u202f': " ",
+	'\u200b': "", '\u2060': "", '\ufeff': "",
+}
+
+type Normalizer struct {
+	widthDict   map[rune]([]rune)
+	composeDict map[int64]rune
+	variantDict map[rune]rune // 异体字 => 正字，文件中一行一组，以 Tab 或空格分割
+}
+
+func NewNormalizer() *Normalizer {
+	n := &Normalizer{}
+	n.widthDict = make(map[rune]([]rune))
+	n.composeDict = make(map[int64]rune)
+	n.variantDict = make(map[rune]rune)
+
+	to := utils.ToRunes(widthTo)
+	for i, r := range utils.ToRunes(widthFrom) {
+		n.widthDict[r] = []rune{to[i]}
+	}
+	for _, item := range strings.Split(widthMulti, "\x00") {
+		runes := utils.ToRunes(item)
+		if len(runes) > 1 {
+			n.widthDict[runes[0]] = runes[1:]
+		}
+	}
+
+	marks := utils.ToRunes(composeMark)
+	composed := utils.ToRunes(composeTo)
+	for i, r := range utils.ToRunes(composeBase) {
+		n.composeDict[composeKey(r, marks[i])] = composed[i]
+	}
+	return n
+}
+
+// 异体字表是可选的，不存在时不做处理
+func (n *Normalizer) LoadVariant(file string) (err error) {
+	err = utils.EachLineIfExist(file, func(line string) {
+		words := strings.Fields(line)
+		if len(words) == 2 {
+			variant := utils.ToRunes(words[0])
+			standard := utils.ToRunes(words[1])
+			if len(variant) == 1 && len(standard) == 1 {
+				n.variantDict[variant[0]] = standard[0]
+			}
+		}
+	})
+	return
+}
+
+// 按 function 指定的方式归一化 text，同时返回归一化后每个字符在原文中的位置，
+// offsets 比结果多一项，最后一项是原文的长度，用于计算词在原文中的结束位置
+func (n *Normalizer) Normalize(text string, function int) (result string, offsets []int) {
+	runes := utils.ToRunes(text)
+	output := make([]rune, 0, len(runes))
+	offsets = make([]int, 0, len(runes)+1)
+
+	for i, r := range runes {
+		seq := []rune{r}
+		if function&NormalizeWidth != 0 {
+			if m, ok := n.widthDict[r]; ok {
+				seq = m
+			}
+		}
+
+		for _, c := range seq {
+			if function&NormalizePunctuation != 0 {
+				if p, ok := punctuationDict[c]; ok {
+					for _, pc := range p {
+						output = append(output, pc)
+						offsets = append(offsets, i)
+					}
+					continue
+				}
+			}
+			if function&NormalizeVariant != 0 {
+				if v, ok := n.variantDict[c]; ok {
+					c = v
+				}
+			}
+			if function&NormalizeCase != 0 {
+				c = unicode.ToLower(unicode.ToUpper(c))
+			}
+
+			// NFC 组合：组合附加符和前一个字符合并成预组合字符，位置沿用前一个字符
+			if function&NormalizeWidth != 0 && len(output) > 0 {
+				if composed, ok := n.composeDict[composeKey(output[len(output)-1], c)]; ok {
+					output[len(output)-1] = composed
+					continue
+				}
+			}
+
+			output = append(output, c)
+			offsets = append(offsets, i)
+		}
+	}
+	offsets = append(offsets, len(runes))
+	return string(output), offsets
+}
+
+func composeKey(base rune, mark rune) int64 {
+	return int64(base)*0x200000 + int64(mark)
+}
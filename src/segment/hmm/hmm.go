@@ -0,0 +1,325 @@
+/**
+ * func:  hidden markov model & viterbi decoding
+ *
+ * model file, one record per line, fields separated by tab:
+ *   S  state  logp              start probability
+ *   T  from   to    logp        transition probability
+ *   N  state  logp              end probability
+ *   E  state  observation logp  emission probability
+ *   U  state  logp              emission probability of unseen observation
+ */
+
+package hmm
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"segment/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var minLogProb = math.Inf(-1)
+
+type Model struct {
+	States  []string
+	stateId map[string]int
+	start   []float64
+	end     []float64
+	hasEnd  bool
+	trans   [][]float64
+	emit    []map[string]float64
+	unseen  []float64
+}
+
+func NewModel() *Model {
+	return &Model{stateId: make(map[string]int)}
+}
+
+func (m *Model) addState(state string) int {
+	if id, ok := m.stateId[state]; ok {
+		return id
+	}
+	id := len(m.States)
+	m.States = append(m.States, state)
+	m.stateId[state] = id
+	m.start = append(m.start, minLogProb)
+	m.end = append(m.end, minLogProb)
+	m.emit = append(m.emit, make(map[string]float64))
+	m.unseen = append(m.unseen, minLogProb)
+	for i := range m.trans {
+		m.trans[i] = append(m.trans[i], minLogProb)
+	}
+	row := make([]float64, len(m.States))
+	for i := range row {
+		row[i] = minLogProb
+	}
+	m.trans = append(m.trans, row)
+	return id
+}
+
+func (m *Model) Load(file string) (err error) {
+	var lineErr error
+	lineNo := 0
+	err = utils.EachLine(file, func(line string) {
+		lineNo++
+		if lineErr != nil || len(line) == 0 {
+			return
+		}
+		fields := strings.Split(line, "\t")
+		logp, e := strconv.ParseFloat(fields[len(fields)-1], 64)
+		if e != nil {
+			lineErr = fmt.Errorf("%s:%d: %v", file, lineNo, e)
+			return
+		}
+		switch {
+		case fields[0] == "S" && len(fields) == 3:
+			m.start[m.addState(fields[1])] = logp
+		case fields[0] == "N" && len(fields) == 3:
+			m.end[m.addState(fields[1])] = logp
+			m.hasEnd = true
+		case fields[0] == "U" && len(fields) == 3:
+			m.unseen[m.addState(fields[1])] = logp
+		case fields[0] == "T" && len(fields) == 4:
+			from := m.addState(fields[1])
+			to := m.addState(fields[2])
+			m.trans[from][to] = logp
+		case fields[0] == "E" && len(fields) == 4:
+			m.emit[m.addState(fields[1])][fields[2]] = logp
+		default:
+			lineErr = fmt.Errorf("%s:%d: bad record", file, lineNo)
+		}
+	})
+	if err == nil {
+		err = lineErr
+	}
+	return
+}
+
+func (m *Model) Save(file string) (err error) {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	for i, s := range m.States {
+		if !math.IsInf(m.start[i], -1) {
+			fmt.Fprintf(w, "S\t%s\t%g\n", s, m.start[i])
+		}
+	}
+	for i, s := range m.States {
+		for j, t := range m.States {
+			if !math.IsInf(m.trans[i][j], -1) {
+				fmt.Fprintf(w, "T\t%s\t%s\t%g\n", s, t, m.trans[i][j])
+			}
+		}
+	}
+	for i, s := range m.States {
+		if m.hasEnd && !math.IsInf(m.end[i], -1) {
+			fmt.Fprintf(w, "N\t%s\t%g\n", s, m.end[i])
+		}
+	}
+	for i, s := range m.States {
+		fmt.Fprintf(w, "U\t%s\t%g\n", s, m.unseen[i])
+		obs := make([]string, 0, len(m.emit[i]))
+		for o := range m.emit[i] {
+			obs = append(obs, o)
+		}
+		sort.Strings(obs)
+		for _, o := range obs {
+			fmt.Fprintf(w, "E\t%s\t%s\t%g\n", s, o, m.emit[i][o])
+		}
+	}
+	return w.Flush()
+}
+
+// 状态 state 发射观察值 obs 的对数概率
+func (m *Model) Emit(state int, obs string) float64 {
+	if p, ok := m.emit[state][obs]; ok {
+		return p
+	}
+	return m.unseen[state]
+}
+
+// 观察值 obs 是否在训练数据中出现过
+func (m *Model) Seen(obs string) bool {
+	for i := range m.emit {
+		if _, ok := m.emit[i][obs]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// 求观察序列 obs 最可能的状态序列。
+// candidates 为 nil 时，每个观察值可以是任何状态，否则 candidates[i] 限定第 i 个观察值的状态，
+// candidates[i] 为空时不限定
+func (m *Model) Viterbi(obs []string, candidates [][]string) []string {
+	if len(obs) == 0 || len(m.States) == 0 {
+		return nil
+	}
+
+	allStates := make([]int, len(m.States))
+	for i := range allStates {
+		allStates[i] = i
+	}
+	statesAt := func(i int) []int {
+		if candidates == nil || len(candidates[i]) == 0 {
+			return allStates
+		}
+		ids := []int{}
+		for _, s := range candidates[i] {
+			if id, ok := m.stateId[s]; ok {
+				ids = append(ids, id)
+			}
+		}
+		if len(ids) == 0 {
+			return allStates
+		}
+		return ids
+	}
+
+	score := make([][]float64, len(obs))
+	back := make([][]int, len(obs))
+	for i := range obs {
+		score[i] = make([]float64, len(m.States))
+		back[i] = make([]int, len(m.States))
+		for j := range score[i] {
+			score[i][j] = minLogProb
+			back[i][j] = -1
+		}
+	}
+
+	prevStates := statesAt(0)
+	for _, s := range prevStates {
+		score[0][s] = m.start[s] + m.Emit(s, obs[0])
+	}
+	for i := 1; i < len(obs); i++ {
+		curStates := statesAt(i)
+		for _, s := range curStates {
+			emit := m.Emit(s, obs[i])
+			for _, p := range prevStates {
+				if v := score[i-1][p] + m.trans[p][s] + emit; back[i][s] < 0 || v > score[i][s] {
+					score[i][s] = v
+					back[i][s] = p
+				}
+			}
+		}
+		prevStates = curStates
+	}
+
+	last := len(obs) - 1
+	best := -1
+	bestScore := minLogProb
+	for _, s := range prevStates {
+		v := score[last][s]
+		if m.hasEnd {
+			v += m.end[s]
+		}
+		if best < 0 || v > bestScore {
+			best = s
+			bestScore = v
+		}
+	}
+
+	result := make([]string, len(obs))
+	for i := last; i >= 0; i-- {
+		result[i] = m.States[best]
+		best = back[i][best]
+		if best < 0 && i > 0 {
+			// 路径不可达时退回第一个候选状态
+			best = statesAt(i - 1)[0]
+		}
+	}
+	return result
+}
+
+// 从标注好的序列统计模型参数，发射概率用加一平滑
+type Trainer struct {
+	model      *Model
+	startCount []float64
+	endCount   []float64
+	transCount [][]float64
+	stateCount []float64
+	emitCount  []map[string]float64
+	vocab      map[string]bool
+	total      float64
+}
+
+func NewTrainer() *Trainer {
+	return &Trainer{model: NewModel(), vocab: make(map[string]bool)}
+}
+
+func (t *Trainer) state(s string) int {
+	id := t.model.addState(s)
+	for len(t.startCount) <= id {
+		t.startCount = append(t.startCount, 0)
+		t.endCount = append(t.endCount, 0)
+		t.stateCount = append(t.stateCount, 0)
+		t.emitCount = append(t.emitCount, make(map[string]float64))
+		for i := range t.transCount {
+			t.transCount[i] = append(t.transCount[i], 0)
+		}
+		t.transCount = append(t.transCount, make([]float64, len(t.model.States)))
+	}
+	return id
+}
+
+// 增加一个标注好的序列，obs 和 states 一一对应
+func (t *Trainer) Add(obs []string, states []string) {
+	if len(obs) == 0 || len(obs) != len(states) {
+		return
+	}
+	prev := -1
+	for i, o := range obs {
+		s := t.state(states[i])
+		if prev < 0 {
+			t.startCount[s]++
+		} else {
+			t.transCount[prev][s]++
+		}
+		t.stateCount[s]++
+		t.emitCount[s][o]++
+		t.vocab[o] = true
+		prev = s
+	}
+	t.endCount[prev]++
+	t.total++
+}
+
+func (t *Trainer) Model() *Model {
+	m := t.model
+	m.hasEnd = true
+	v := float64(len(t.vocab))
+	for s := range m.States {
+		m.start[s] = logDiv(t.startCount[s], t.total)
+		m.end[s] = logDiv(t.endCount[s], t.total)
+
+		out := 0.0
+		for _, c := range t.transCount[s] {
+			out += c
+		}
+		for n, c := range t.transCount[s] {
+			m.trans[s][n] = logDiv(c, out)
+		}
+
+		m.emit[s] = make(map[string]float64)
+		for o, c := range t.emitCount[s] {
+			m.emit[s][o] = math.Log((c + 1) / (t.stateCount[s] + v + 1))
+		}
+		m.unseen[s] = math.Log(1 / (t.stateCount[s] + v + 1))
+	}
+	return m
+}
+
+func logDiv(a float64, b float64) float64 {
+	if a == 0 || b == 0 {
+		return minLogProb
+	}
+	return math.Log(a / b)
+}
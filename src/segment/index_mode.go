@@ -0,0 +1,135 @@
+package segment
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/match"
+	"segment/utils"
+	"sort"
+)
+
+// 索引模式：在每个词后面输出它包含的所有词典中的词（单字除外）。
+// 被包含词的嵌套深度是包含它的最长一串词的层数，直接被选出的词包含的是第一层，
+// 权值为 IndexSubWordRank，每深一层减一。识别出来的地名、机构名中包含的词同样输出
+func (s *Segment) addSubWords(words *list.List) {
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		length := utils.RuneLen(wi.Word)
+		if length <= 2 {
+			continue
+		}
+
+		subs := []dict.PositionLength{}
+		for _, pl := range s.wordDictionary.GetAllMatchs(wi.Word, false, s.recognizers()...) {
+			if pl.Length > 1 && pl.Length < length {
+				subs = append(subs, pl)
+			}
+		}
+		if len(subs) == 0 {
+			continue
+		}
+
+		// 先算长的词，被包含的词的深度是包含它的词的深度加一
+		sort.SliceStable(subs, func(i, j int) bool {
+			return subs[i].Length > subs[j].Length
+		})
+		depth := make([]int, len(subs))
+		for i, pl := range subs {
+			depth[i] = 1
+			for j := 0; j < i; j++ {
+				outer := subs[j]
+				if outer.Length > pl.Length && outer.Position <= pl.Position && pl.Position+pl.Length <= outer.Position+outer.Length && depth[j]+1 > depth[i] {
+					depth[i] = depth[j] + 1
+				}
+			}
+		}
+
+		order := make([]int, len(subs))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(i, j int) bool {
+			return subs[order[i]].Position < subs[order[j]].Position
+		})
+
+		mark := cur
+		for _, i := range order {
+			pl := subs[i]
+			if s.containsWord(words, wi.Position+pl.Position, pl.Length) {
+				continue
+			}
+			rank := s.params.IndexSubWordRank - depth[i] + 1
+			if rank < 1 {
+				rank = 1
+			}
+			word := string(utils.ToRunes(wi.Word)[pl.Position:(pl.Position + pl.Length)])
+			sub := dict.NewWordInfo(word, wi.Position+pl.Position, pl.WordAttri.Pos, pl.WordAttri.Frequency, rank, wi.WordType, wi.OriginalWordType)
+			mark = words.InsertAfter(sub, mark)
+		}
+		cur = mark
+	}
+}
+
+// 多元分词时同一个词可能已经输出过
+func (s *Segment) containsWord(words *list.List, position int, length int) bool {
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		if wi.Position == position && utils.RuneLen(wi.Word) == length {
+			return true
+		}
+	}
+	return false
+}
+
+// 查询模式只用最优的一种切分，不输出多元分词的其他结果和强制输出的单字
+func (s *Segment) queryOptions() *match.MatchOptions {
+	options := *s.options
+	options.MultiDimensionality = false
+	options.ForceSingleWord = false
+	return &options
+}
+
+// 查询模式：在最优切分的基础上，相邻的几个词正好是词典中的一个词时合并成这个词，取最长的，
+// 所以输出的词互不重叠并且覆盖全部文本。带有猜测惩罚的候选词不合并，保持最优切分的选择
+func (s *Segment) mergeQueryWords(words *list.List, pls []dict.PositionLength, text string) {
+	longest := make(map[int]dict.PositionLength)
+	for _, pl := range pls {
+		if pl.WordAttri.Penalty == 0 && pl.Length > longest[pl.Position].Length {
+			longest[pl.Position] = pl
+		}
+	}
+	runes := utils.ToRunes(text)
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		pl, ok := longest[wi.Position]
+		if !ok || pl.Length <= utils.RuneLen(wi.Word) {
+			continue
+		}
+
+		// 合并的词必须在某个词的结尾处结束
+		end := pl.Position + pl.Length
+		last := cur
+		for next := cur.Next(); next != nil; next = next.Next() {
+			nwi := next.Value.(*dict.WordInfo)
+			if nwi.Position+utils.RuneLen(nwi.Word) > end {
+				break
+			}
+			last = next
+		}
+		lwi := last.Value.(*dict.WordInfo)
+		if last == cur || lwi.Position+utils.RuneLen(lwi.Word) != end {
+			continue
+		}
+
+		for last != cur {
+			prev := last.Prev()
+			words.Remove(last)
+			last = prev
+		}
+		wi.Word = string(runes[pl.Position:end])
+		wi.Pos = pl.WordAttri.Pos
+		wi.Frequency = pl.WordAttri.Frequency
+		wi.Score = pl.WordAttri.Score
+		wi.Rank = s.params.BestRank
+	}
+}
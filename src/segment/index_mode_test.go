@@ -0,0 +1,82 @@
+package segment
+
+import (
+	"math/rand"
+	"segment/dict"
+	"segment/match"
+	"segment/utils"
+	"strings"
+	"testing"
+)
+
+// 随机拼接词典中的词，有时只取词的第一个字
+func randomTexts(t *testing.T, count int) []string {
+	words := []string{}
+	utils.EachLine(testDictPath+"/Dict.txt", func(line string) {
+		fields := strings.Split(strings.TrimPrefix(line, "\ufeff"), "|")
+		if len(fields) == 3 && utils.RuneLen(fields[0]) <= 4 {
+			words = append(words, fields[0])
+		}
+	})
+	if len(words) == 0 {
+		t.Skip("dictionary not available")
+	}
+	texts := []string{"研究生命起源", "长春市长春节讲话", "结婚的和尚未结婚的", "中华人民共和国成立了", "北京大学生活"}
+	r := rand.New(rand.NewSource(1))
+	for len(texts) < count {
+		text := ""
+		for n := 1 + r.Intn(8); n > 0; n-- {
+			word := words[r.Intn(len(words))]
+			if r.Intn(3) == 0 {
+				word = string(utils.ToRunes(word)[:1])
+			}
+			text += word
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+// 查询模式输出的词互不重叠，按顺序拼起来就是原文
+func TestQueryModeCoversText(t *testing.T) {
+	s := loadTestSegment(t)
+	texts := randomTexts(t, 1000)
+	for _, redundancy := range []int{0, 2} {
+		for _, forceSingleWord := range []bool{false, true} {
+			options := match.NewMatchOptions()
+			options.QueryMode = true
+			options.FilterStopWords = false
+			options.ForceSingleWord = forceSingleWord
+			params := match.NewMatchParameter()
+			params.Redundancy = redundancy
+			for _, text := range texts {
+				words := []string{}
+				position := 0
+				for cur := s.DoSegmentWithOptionParam(text, options, params).Front(); cur != nil; cur = cur.Next() {
+					wi := cur.Value.(*dict.WordInfo)
+					if wi.Position != position {
+						t.Fatalf("%s (Redundancy=%d, ForceSingleWord=%v): %s at %d, want %d",
+							text, redundancy, forceSingleWord, wi.Word, wi.Position, position)
+					}
+					words = append(words, wi.Word)
+					position += utils.RuneLen(wi.Word)
+				}
+				if strings.Join(words, "") != text {
+					t.Fatalf("%s (Redundancy=%d, ForceSingleWord=%v): got %s", text, redundancy, forceSingleWord, strings.Join(words, "/"))
+				}
+			}
+		}
+	}
+}
+
+// 查询模式的词和不带查询模式的最优切分一致
+func TestQueryModeBestPath(t *testing.T) {
+	options := match.NewMatchOptions()
+	options.QueryMode = true
+	options.FilterStopWords = false
+	checkSegments(t, options, map[string]string{
+		"研究生命起源":     "研究/生命/起源",
+		"长春市长春节讲话":   "长春(ns)/市长/春节/讲话",
+		"中华人民共和国成立了": "中华人民共和国(ns)/成立/了",
+	})
+}
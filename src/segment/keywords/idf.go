@@ -0,0 +1,107 @@
+package keywords
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"segment"
+	"segment/match"
+	"segment/utils"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const IDFFileName = "Idf.txt"
+
+// 逆文档频率表，表中没有的词使用 Default
+type IDF struct {
+	Default float64 // 没有出现过的词的 IDF，加载时设为表中所有值的中位数
+	values  map[string]float64
+}
+
+// 每行的格式为 词|IDF
+func LoadIDF(file string) (*IDF, error) {
+	idf := &IDF{Default: 1, values: make(map[string]float64)}
+	err := utils.EachLine(file, func(line string) {
+		fields := strings.Split(strings.TrimSpace(strings.TrimPrefix(line, "\ufeff")), "|")
+		if len(fields) != 2 {
+			return
+		}
+		if v, e := strconv.ParseFloat(fields[1], 64); e == nil {
+			idf.values[fields[0]] = v
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(idf.values) > 0 {
+		values := make([]float64, 0, len(idf.values))
+		for _, v := range idf.values {
+			values = append(values, v)
+		}
+		sort.Float64s(values)
+		idf.Default = values[len(values)/2]
+	}
+	return idf, nil
+}
+
+// idf 为 nil 时返回 1
+func (idf *IDF) Get(word string) float64 {
+	if idf == nil {
+		return 1
+	}
+	if v, ok := idf.values[word]; ok {
+		return v
+	}
+	return idf.Default
+}
+
+// 从文档集合统计每个词出现的文档数，生成 IDF 表
+type IDFBuilder struct {
+	Options *match.MatchOptions // 分词选项，要和提取关键词时相同
+	MinDF   int                 // 出现的文档数少于 MinDF 的词不写入 IDF 表
+
+	seg  *segment.Segment
+	docs int
+	df   map[string]int
+}
+
+func NewIDFBuilder(seg *segment.Segment) *IDFBuilder {
+	return &IDFBuilder{Options: NewMatchOptions(), MinDF: 1, seg: seg, df: make(map[string]int)}
+}
+
+func (b *IDFBuilder) Add(document string) {
+	b.docs++
+	seen := make(map[string]bool)
+	for _, t := range Terms(b.seg, document, b.Options) {
+		if !seen[t.Word] {
+			seen[t.Word] = true
+			b.df[t.Word]++
+		}
+	}
+}
+
+// IDF = ln(文档数 / 出现的文档数)，按词排序写入
+func (b *IDFBuilder) Save(file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	words := make([]string, 0, len(b.df))
+	for word, df := range b.df {
+		if df >= b.MinDF {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+
+	w := bufio.NewWriter(f)
+	for _, word := range words {
+		fmt.Fprintf(w, "%s|%.6f\n", word, math.Log(float64(b.docs)/float64(b.df[word])))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,113 @@
+/**
+ * func:  keyword extraction
+ *
+ * 分词后去掉停用词、虚词、标点和单字，按 TF × IDF 给词打分，取分数最高的词作为关键词。
+ */
+
+package keywords
+
+import (
+	"segment"
+	"segment/dict"
+	"segment/match"
+	"segment/utils"
+	"sort"
+)
+
+// 默认去掉的词性：副词、介词、连词、助词、代词、叹词、拟声词、语气词、标点、数词、量词
+const DefaultExcludePos = dict.POS_D_D | dict.POS_D_P | dict.POS_D_C | dict.POS_D_U | dict.POS_D_R |
+	dict.POS_D_E | dict.POS_D_O | dict.POS_D_Y | dict.POS_D_W | dict.POS_A_M | dict.POS_A_Q | dict.POS_D_MQ
+
+// 提取关键词用的分词选项：不输出多元分词结果，过滤停用词和 DefaultExcludePos 中的词性。
+// 建立 IDF 表和提取关键词要使用相同的选项
+func NewMatchOptions() *match.MatchOptions {
+	options := match.NewMatchOptions()
+	options.MultiDimensionality = false
+	options.FilterStopWords = true
+	options.IgnoreCapital = true
+	options.ExcludePos = DefaultExcludePos
+	return options
+}
+
+// 分词结果中的一个词，Position 是在原文中的位置（按字符计）
+type Term struct {
+	Word     string
+	Position int
+	Length   int
+	Pos      int
+}
+
+// 关键词和它在原文中每次出现的位置
+type Keyword struct {
+	Word      string  `json:"word"`
+	Weight    float64 `json:"weight"`
+	Positions []int   `json:"positions"`
+	Length    int     `json:"length"` // 在原文中的字符数
+}
+
+// 分词并去掉单字、数字和符号，只保留中文词和英文词
+func Terms(seg *segment.Segment, text string, options *match.MatchOptions) []Term {
+	terms := []Term{}
+	for cur := seg.DoSegmentWithOption(text, options).Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		if wi.WordType != dict.TSimplifiedChinese && wi.WordType != dict.TEnglish {
+			continue
+		}
+		if utils.RuneLen(wi.Word) < 2 {
+			continue
+		}
+		length := wi.OriginalLength
+		terms = append(terms, Term{Word: wi.Word, Position: wi.Position, Length: length, Pos: wi.EffectivePos()})
+	}
+	return terms
+}
+
+// 按 TF × IDF 提取关键词
+type Extractor struct {
+	Options *match.MatchOptions
+
+	seg *segment.Segment
+	idf *IDF
+}
+
+// idf 为 nil 时所有词的 IDF 都是 1，只按词频排序
+func NewExtractor(seg *segment.Segment, idf *IDF) *Extractor {
+	return &Extractor{Options: NewMatchOptions(), seg: seg, idf: idf}
+}
+
+// 权值最高的 k 个关键词，权值相同时按第一次出现的位置排列，k <= 0 时返回全部
+func (e *Extractor) TopK(text string, k int) []Keyword {
+	terms := Terms(e.seg, text, e.Options)
+	keywords := groupTerms(terms)
+	for i := range keywords {
+		tf := float64(len(keywords[i].Positions)) / float64(len(terms))
+		keywords[i].Weight = tf * e.idf.Get(keywords[i].Word)
+	}
+	return topK(keywords, k)
+}
+
+// 把同一个词的多次出现合在一起，按第一次出现的位置排列
+func groupTerms(terms []Term) []Keyword {
+	index := make(map[string]int)
+	keywords := []Keyword{}
+	for _, t := range terms {
+		i, ok := index[t.Word]
+		if !ok {
+			i = len(keywords)
+			index[t.Word] = i
+			keywords = append(keywords, Keyword{Word: t.Word, Length: t.Length})
+		}
+		keywords[i].Positions = append(keywords[i].Positions, t.Position)
+	}
+	return keywords
+}
+
+func topK(keywords []Keyword, k int) []Keyword {
+	sort.SliceStable(keywords, func(i, j int) bool {
+		return keywords[i].Weight > keywords[j].Weight
+	})
+	if k > 0 && len(keywords) > k {
+		keywords = keywords[:k]
+	}
+	return keywords
+}
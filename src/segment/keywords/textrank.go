@@ -0,0 +1,272 @@
+package keywords
+
+import (
+	"math"
+	"segment"
+	"segment/match"
+	"segment/utils"
+	"sort"
+	"strings"
+)
+
+// 分句用的标点
+const SENTENCE_DELIMITERS = "。！？!?；;…\n"
+
+// 一个句子和它在原文中的位置（按字符计）
+type Sentence struct {
+	Text     string  `json:"text"`
+	Position int     `json:"position"`
+	Length   int     `json:"length"`
+	Weight   float64 `json:"weight"`
+}
+
+// 基于图的关键词、关键短语和摘要句提取，不需要 IDF 表：
+// 关键词用滑动窗口内的共现关系构图，摘要句用句子之间相同的词构图，再用 PageRank 计算每个节点的权值
+type TextRank struct {
+	Options    *match.MatchOptions // 分词选项，默认和 TF-IDF 提取相同
+	Window     int                 // 共现窗口的大小，窗口内的词两两相连
+	Damping    float64             // 阻尼系数
+	Iterations int                 // 最多迭代的次数
+	Tolerance  float64             // 两次迭代的权值变化都小于这个值时停止
+
+	seg *segment.Segment
+}
+
+func NewTextRank(seg *segment.Segment) *TextRank {
+	return &TextRank{Options: NewMatchOptions(), Window: 5, Damping: 0.85, Iterations: 100, Tolerance: 1e-6, seg: seg}
+}
+
+// 权值最高的 k 个关键词，权值归一化到最大为 1，k <= 0 时返回全部
+func (t *TextRank) Keywords(text string, k int) []Keyword {
+	return t.keywords(Terms(t.seg, text, t.Options), k)
+}
+
+func (t *TextRank) keywords(terms []Term, k int) []Keyword {
+	keywords := groupTerms(terms)
+	index := make(map[string]int, len(keywords))
+	for i, kw := range keywords {
+		index[kw.Word] = i
+	}
+
+	graph := make([]map[int]float64, len(keywords))
+	for i := range graph {
+		graph[i] = make(map[int]float64)
+	}
+	for i := range terms {
+		a := index[terms[i].Word]
+		for j := i + 1; j < len(terms) && j < i+t.Window; j++ {
+			if b := index[terms[j].Word]; a != b {
+				graph[a][b]++
+				graph[b][a]++
+			}
+		}
+	}
+
+	for i, w := range t.pageRank(graph) {
+		keywords[i].Weight = w
+	}
+	return topK(keywords, k)
+}
+
+// 把原文中紧挨着的关键词合成关键短语，如 自然/语言/处理。
+// 参与合并的是权值最高的三分之一的词（至少 k 个），短语的权值是其中的词的权值之和
+func (t *TextRank) KeyPhrases(text string, k int) []Keyword {
+	terms := Terms(t.seg, text, t.Options)
+	ranked := t.keywords(terms, 0)
+	n := len(ranked) / 3
+	if n < k {
+		n = k
+	}
+	if n > len(ranked) {
+		n = len(ranked)
+	}
+	weight := make(map[string]float64, n)
+	for _, kw := range ranked[:n] {
+		weight[kw.Word] = kw.Weight
+	}
+
+	runes := utils.ToRunes(text)
+	index := make(map[string]int)
+	phrases := []Keyword{}
+	for i := 0; i < len(terms); {
+		j := i + 1
+		if _, ok := weight[terms[i].Word]; ok {
+			for j < len(terms) && terms[j].Position == terms[j-1].Position+terms[j-1].Length {
+				if _, ok := weight[terms[j].Word]; !ok {
+					break
+				}
+				j++
+			}
+		}
+		if j-i < 2 {
+			i++
+			continue
+		}
+
+		start, end := terms[i].Position, terms[j-1].Position+terms[j-1].Length
+		phrase := string(runes[start:end])
+		p, ok := index[phrase]
+		if !ok {
+			p = len(phrases)
+			index[phrase] = p
+			w := 0.0
+			for _, term := range terms[i:j] {
+				w += weight[term.Word]
+			}
+			phrases = append(phrases, Keyword{Word: phrase, Weight: w, Length: end - start})
+		}
+		phrases[p].Positions = append(phrases[p].Positions, start)
+		i = j
+	}
+	return topK(phrases, k)
+}
+
+// 摘要：按句子之间的相似度排序，取权值最高的 n 个句子，按在原文中的顺序排列。
+// 两个句子的相似度是相同的词数除以两个句子词数的对数之和
+func (t *TextRank) Summary(text string, n int) []Sentence {
+	sentences := SplitSentences(text)
+	if len(sentences) == 0 {
+		return nil
+	}
+
+	// 每个句子包含的词
+	words := make([]map[string]bool, len(sentences))
+	counts := make([]int, len(sentences))
+	for i := range words {
+		words[i] = make(map[string]bool)
+	}
+	s := 0
+	for _, term := range Terms(t.seg, text, t.Options) {
+		for s < len(sentences)-1 && term.Position >= sentences[s].Position+sentences[s].Length {
+			s++
+		}
+		words[s][term.Word] = true
+		counts[s]++
+	}
+
+	graph := make([]map[int]float64, len(sentences))
+	for i := range graph {
+		graph[i] = make(map[int]float64)
+	}
+	for i := range sentences {
+		for j := i + 1; j < len(sentences); j++ {
+			if counts[i] < 2 || counts[j] < 2 {
+				continue
+			}
+			common := 0
+			for w := range words[i] {
+				if words[j][w] {
+					common++
+				}
+			}
+			if common > 0 {
+				sim := float64(common) / (math.Log(float64(counts[i])) + math.Log(float64(counts[j])))
+				graph[i][j] = sim
+				graph[j][i] = sim
+			}
+		}
+	}
+
+	for i, w := range t.pageRank(graph) {
+		sentences[i].Weight = w
+	}
+	order := make([]int, len(sentences))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return sentences[order[i]].Weight > sentences[order[j]].Weight
+	})
+	if n > 0 && len(order) > n {
+		order = order[:n]
+	}
+	sort.Ints(order)
+
+	result := make([]Sentence, len(order))
+	for i, o := range order {
+		result[i] = sentences[o]
+	}
+	return result
+}
+
+// 按 SENTENCE_DELIMITERS 分句，标点留在句子末尾，去掉两边的空白
+func SplitSentences(text string) []Sentence {
+	runes := utils.ToRunes(text)
+	sentences := []Sentence{}
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && !strings.ContainsRune(SENTENCE_DELIMITERS, runes[i]) {
+			continue
+		}
+		end := i
+		if i < len(runes) && runes[i] != '\n' {
+			end++
+		}
+		for start < end && isSpace(runes[start]) {
+			start++
+		}
+		last := end
+		for last > start && isSpace(runes[last-1]) {
+			last--
+		}
+		if last > start {
+			sentences = append(sentences, Sentence{Text: string(runes[start:last]), Position: start, Length: last - start})
+		}
+		start = i + 1
+	}
+	return sentences
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == '　'
+}
+
+// 带权的 PageRank，返回的权值归一化到最大为 1
+func (t *TextRank) pageRank(graph []map[int]float64) []float64 {
+	n := len(graph)
+	if n == 0 {
+		return nil
+	}
+	outSum := make([]float64, n)
+	for i, edges := range graph {
+		for _, w := range edges {
+			outSum[i] += w
+		}
+	}
+
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = 1
+	}
+	next := make([]float64, n)
+	for iter := 0; iter < t.Iterations; iter++ {
+		for i := range next {
+			next[i] = 1 - t.Damping
+		}
+		for j, edges := range graph {
+			if outSum[j] == 0 {
+				continue
+			}
+			for i, w := range edges {
+				next[i] += t.Damping * w / outSum[j] * scores[j]
+			}
+		}
+		diff := 0.0
+		for i := range scores {
+			diff = math.Max(diff, math.Abs(next[i]-scores[i]))
+		}
+		scores, next = next, scores
+		if diff < t.Tolerance {
+			break
+		}
+	}
+
+	max := 0.0
+	for _, s := range scores {
+		max = math.Max(max, s)
+	}
+	for i := range scores {
+		scores[i] /= max
+	}
+	return scores
+}
@@ -7,9 +7,10 @@ import (
 	"sort"
 )
 
+// 中文匹配器，根据词典匹配的结果 posLenArr 对一段中文切分，见 RegisterMatcher
 type IChsFullTextMatch interface {
 	SetOptionParams(options *MatchOptions, params *MatchParameter)
-	Match(posLenArr []dict.PositionLength, originalText string, count int) *list.List
+	Match(posLenArr []dict.PositionLength, originalText string) *list.List
 }
 
 const (
@@ -22,10 +23,8 @@ type ChsFullTextMatch struct {
 	params          *MatchParameter
 	wordDict        *dict.WordDictionary
 	root            *Node
-	leafNodeList    []*Node
-	posLenArr       []dict.PositionLength
-	inputStringLen  int
 	allCombinations []([]dict.PositionLength)
+	trace           *MatchTrace
 }
 
 var freqFirst bool
@@ -33,7 +32,6 @@ var freqFirst bool
 func NewChsFullTextMatch(wdict *dict.WordDictionary) (m *ChsFullTextMatch) {
 	m = &ChsFullTextMatch{wordDict: wdict}
 	m.root = NewNode()
-	m.leafNodeList = [](*Node){}
 	m.allCombinations = make([]([]dict.PositionLength), 0)
 	return
 }
@@ -43,6 +41,11 @@ func (m *ChsFullTextMatch) SetOptionParams(options *MatchOptions, params *MatchP
 	m.params = params 
 }
 
+// 记录匹配过程，见 MatchTrace
+func (m *ChsFullTextMatch) SetTrace(trace *MatchTrace) {
+	m.trace = trace
+}
+
 func (m *ChsFullTextMatch) Match(posLenArr []dict.PositionLength, originalText string) *list.List {
 	if m.options == nil {
 		m.options = NewMatchOptions()
@@ -56,7 +59,14 @@ func (m *ChsFullTextMatch) Match(posLenArr []dict.PositionLength, originalText s
 
 	result := list.New()
 	if len(posLenArr) == 0 {
-		if m.options.UnknownWordIdentify {
+		if m.hmmEnabled() {
+			unknownWords := []*dict.WordInfo{}
+			m.hmmUnknownWords(masks, runes, 0, len(runes), &unknownWords)
+			for _, wi := range unknownWords {
+				result.PushBack(wi)
+			}
+			return result
+		} else if m.options.UnknownWordIdentify {
 			wi := dict.NewWordInfoDefault()
 			wi.Word = originalText
 			wi.Position = 0
@@ -114,6 +124,12 @@ func (m *ChsFullTextMatch) Match(posLenArr []dict.PositionLength, originalText s
 		m.allCombinations = append(m.allCombinations, comb)
 	}
 
+	if m.trace != nil {
+		for _, comb := range m.allCombinations {
+			m.trace.addCombination(comb, runes)
+		}
+	}
+
 	if len(m.allCombinations) > 0 {
 		positionCollection := m.mergeAllCombinations(redundancy)
 		curPc := positionCollection.Front()
@@ -123,6 +139,7 @@ func (m *ChsFullTextMatch) Match(posLenArr []dict.PositionLength, originalText s
 			wi.Word = string(runes[pl.Position:(pl.Position + pl.Length)])
 			wi.Pos = pl.WordAttri.Pos
 			wi.Frequency = pl.WordAttri.Frequency
+			wi.Score = pl.WordAttri.Score
 			wi.WordType = dict.TSimplifiedChinese
 			wi.Position = pl.Position
 			switch pl.Level {
@@ -152,6 +169,9 @@ func (m *ChsFullTextMatch) Match(posLenArr []dict.PositionLength, originalText s
 
 	// 合并未登录词
 	unknownWords, needRemoveSingleWord := m.getUnknownWords(masks, runes)
+	if m.trace != nil {
+		m.trace.Masks = append([]int{}, masks...)
+	}
 	// 合并结果序列到对应位置中
 	if len(unknownWords) > 0 {
 		cur := result.Front()
@@ -210,8 +230,10 @@ func (m *ChsFullTextMatch) getLeafNodeArray(posLenArr []dict.PositionLength, ori
 			for j := 0; j < c; j++ {
 				arr[j] = posLenArr[lastIndex+j]
 			}
-			leafNodeArray := m.getLeafNodeArrayCore(arr, lastRightBoundary-posLenArr[lastIndex].Position)
-			sort.Sort(Nodes(leafNodeArray))
+			leafNodeArray := m.getLeafNodeArrayCore(arr, lastRightBoundary-posLenArr[lastIndex].Position, TopRecord)
+			if m.trace != nil {
+				m.trace.addSpan(posLenArr[lastIndex].Position, lastRightBoundary-posLenArr[lastIndex].Position, leafNodeArray, utils.ToRunes(originalText))
+			}
 			m.combineNodeAttr(result, leafNodeArray)
 			lastIndex = i
 		}
@@ -229,20 +251,98 @@ func (m *ChsFullTextMatch) getLeafNodeArray(posLenArr []dict.PositionLength, ori
 		for j := 0; j < c; j++ {
 			arr[j] = posLenArr[lastIndex+j]
 		}
-		leafNodeArray := m.getLeafNodeArrayCore(arr, lastRightBoundary-posLenArr[lastIndex].Position)
-		sort.Sort(Nodes(leafNodeArray))
+		leafNodeArray := m.getLeafNodeArrayCore(arr, lastRightBoundary-posLenArr[lastIndex].Position, TopRecord)
+		if m.trace != nil {
+			m.trace.addSpan(posLenArr[lastIndex].Position, lastRightBoundary-posLenArr[lastIndex].Position, leafNodeArray, utils.ToRunes(originalText))
+		}
 		m.combineNodeAttr(result, leafNodeArray)
 	}
 
 	return result
 }
 
-func (m *ChsFullTextMatch) getLeafNodeArrayCore(posLenArr []dict.PositionLength, orginalTextLength int) []*Node {
-	m.leafNodeList = [](*Node){}
-	m.posLenArr = posLenArr
-	m.inputStringLen = orginalTextLength
-	m.buildTree(m.root, 0)	
-	return m.leafNodeList
+// 用动态规划求出片段中排在最前面的 count 条路径，按 pathCost.less 的规则排好序。
+// 一个词后面只能接它之后第一个有词开始的位置上的词，各项指标又都是沿路径累加的，
+// 所以从后往前算出以每个词开始的前 count 条路径，前面的词只需要在这些路径中选择。
+// 其他指标相同时词频之和大的优先，词频之和也相同的路径，在 posLenArr 中靠前的词优先。
+// 原来不是词频优先时不计算词频之和，这些路径遍历之后用 sort.Sort 排序，先后是不确定的，
+// 所以多元分词输出的第二、三种切分可能和原来不同，如 第三十 现在是 第三/十 在 第/三十 之前；
+// 各名次上的未覆盖字数、词数和单字数都不变
+func (m *ChsFullTextMatch) getLeafNodeArrayCore(posLenArr []dict.PositionLength, orginalTextLength int, count int) []*Node {
+	best := make([][]*pathCost, len(posLenArr))
+	for i := len(posLenArr) - 1; i >= 0; i-- {
+		pl := posLenArr[i]
+		step := &pathCost{aboveCount: 1 + pl.WordAttri.Penalty, index: i, freqSum: pl.WordAttri.Frequency}
+		if pl.Length == 1 {
+			step.singleWordCount = 1
+		}
+
+		end := pl.Position + pl.Length
+		next := i + 1
+		for next < len(posLenArr) && posLenArr[next].Position < end {
+			next++
+		}
+		if next >= len(posLenArr) {
+			step.spaceCount = orginalTextLength - end
+			best[i] = []*pathCost{step}
+		} else {
+			step.spaceCount = posLenArr[next].Position - end
+			best[i] = topPaths(step, best, posLenArr, next, count)
+		}
+	}
+
+	// 从第一个词之前开始的路径，不对应任何词
+	start := &pathCost{spaceCount: posLenArr[0].Position, index: -1}
+	leafNodeArray := [](*Node){}
+	for _, path := range topPaths(start, best, posLenArr, 0, count) {
+		leafNodeArray = append(leafNodeArray, m.buildNodes(path.next, posLenArr, orginalTextLength))
+	}
+	return leafNodeArray
+}
+
+// 以 step 开始，后面接 first 位置上各个词开始的路径，取最前面的 count 条
+func topPaths(step *pathCost, best [][]*pathCost, posLenArr []dict.PositionLength, first int, count int) []*pathCost {
+	paths := []*pathCost{}
+	for j := first; j < len(posLenArr) && posLenArr[j].Position == posLenArr[first].Position; j++ {
+		for _, next := range best[j] {
+			path := *step
+			path.spaceCount += next.spaceCount
+			path.aboveCount += next.aboveCount
+			path.singleWordCount += next.singleWordCount
+			path.freqSum += next.freqSum
+			path.next = next
+			paths = append(paths, &path)
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return paths[i].less(paths[j])
+	})
+	if len(paths) > count {
+		paths = paths[:count]
+	}
+	return paths
+}
+
+// 把路径转换成从叶子指向根的 Node 链表，每个节点上的指标是到这个节点为止的累加值
+func (m *ChsFullTextMatch) buildNodes(path *pathCost, posLenArr []dict.PositionLength, orginalTextLength int) *Node {
+	node := m.root
+	for ; path != nil; path = path.next {
+		pl := posLenArr[path.index]
+		spaceCount := node.SpaceCount + pl.Position - (node.PosLen.Position + node.PosLen.Length)
+		singleWordCount := node.SingleWordCount
+		if pl.Length == 1 {
+			singleWordCount++
+		}
+		freqSum := 0.0
+		if m.options != nil && m.options.FrequencyFirst {
+			freqSum = node.FreqSum + pl.WordAttri.Frequency
+		}
+		penalty := node.Penalty + pl.WordAttri.Penalty
+		node = NewNodeFull(pl, node, node.AboveCount+1, spaceCount, singleWordCount, freqSum)
+		node.Penalty = penalty
+	}
+	node.SpaceCount += orginalTextLength - node.PosLen.Position - node.PosLen.Length
+	return node
 }
 
 func (m *ChsFullTextMatch) combineNodeAttr(result []*Node, arr []*Node) {
@@ -324,8 +424,10 @@ func (m *ChsFullTextMatch) combineNodeAttr(result []*Node, arr []*Node) {
 
 			n.Parent = result[i]
 			aboveCount := arr[j].AboveCount + result[i].AboveCount
+			penalty := arr[j].Penalty + result[i].Penalty
 			result[i] = arr[j]
 			result[i].AboveCount = aboveCount
+			result[i].Penalty = penalty
 		}
 	}
 }
@@ -400,7 +502,12 @@ func (m *ChsFullTextMatch) getUnknownWords(masks []int, orginalText []rune) (unk
 			} else {
 				mergeUnknownWord := true
 				if !m.isKnownSingleWord(masks, j, orginalText) {
-					if j-beginPosition <= 2 {
+					if m.hmmEnabled() {
+						mergeUnknownWord = false
+						if m.hmmUnknownWords(masks, orginalText, beginPosition, j, &unknownWords) {
+							needRemoveSingleWord = true
+						}
+					} else if j-beginPosition <= 2 {
 						for k := beginPosition; k < j; k++ {
 							mergeUnknownWord = false
 							if masks[k] != 1 {
@@ -451,7 +558,12 @@ func (m *ChsFullTextMatch) getUnknownWords(masks []int, orginalText []rune) (unk
 
 	if begin && m.options.UnknownWordIdentify {
 		mergeUnknownWord := true
-		if j-beginPosition <= 2 {
+		if m.hmmEnabled() {
+			mergeUnknownWord = false
+			if m.hmmUnknownWords(masks, orginalText, beginPosition, j, &unknownWords) {
+				needRemoveSingleWord = true
+			}
+		} else if j-beginPosition <= 2 {
 			for k := beginPosition; k < j; k++ {
 				mergeUnknownWord = false
 				if masks[k] != 1 {
@@ -488,6 +600,36 @@ func (m *ChsFullTextMatch) getUnknownWords(masks []int, orginalText []rune) (unk
 	return
 }
 
+func (m *ChsFullTextMatch) hmmEnabled() bool {
+	return m.options.UnknownWordIdentify && m.options.HmmUnknownWord && m.wordDict.UnknownWordModel != nil
+}
+
+// 用 HMM 字标注模型切分 [begin, end) 之间的未登录词片段，多字词作为未登录词输出，
+// 单字词和原来一样，没有匹配过的单字才输出
+func (m *ChsFullTextMatch) hmmUnknownWords(masks []int, orginalText []rune, begin int, end int, unknownWords *[]*dict.WordInfo) (needRemoveSingleWord bool) {
+	pos := begin
+	for _, l := range hmmCut(m.wordDict.UnknownWordModel, orginalText[begin:end]) {
+		if l == 1 && masks[pos] == 1 {
+			pos++
+			continue
+		}
+		for k := pos; k < pos+l; k++ {
+			if l > 1 && masks[k] == 1 {
+				masks[k] = 11
+				needRemoveSingleWord = true
+			}
+		}
+		wi := dict.NewWordInfoDefault()
+		wi.Word = string(orginalText[pos:(pos + l)])
+		wi.Position = pos
+		wi.WordType = dict.TNone
+		wi.Rank = m.params.UnknowRank
+		*unknownWords = append(*unknownWords, wi)
+		pos += l
+	}
+	return
+}
+
 func (m *ChsFullTextMatch) isKnownSingleWord(masks []int, index int, orginalText []rune) bool {
 	state := masks[index]
 	if state == 2 {
@@ -510,6 +652,7 @@ func (m *ChsFullTextMatch) isKnownSingleWord(masks []int, index int, orginalText
 
 type Node struct {
 	AboveCount      int
+	Penalty         int // 路径上猜测出来的词额外计算的词数，排序时和 AboveCount 相加，见 dict.WordAttr.Penalty
 	SpaceCount      int
 	FreqSum         float64
 	SingleWordCount int
@@ -517,55 +660,32 @@ type Node struct {
 	Parent          *Node
 }
 
-type Nodes [](*Node)
-
-func (nodes Nodes) Len() int {
-	return len(nodes)
+// 从某个词开始到片段结尾的一条路径，指标是路径上所有词的累加值
+type pathCost struct {
+	spaceCount      int
+	aboveCount      int // 词数，猜测出来的候选词加上 WordAttr.Penalty
+	singleWordCount int
+	freqSum         float64   // 不是词频优先时也计算，作为最后的比较条件
+	index           int       // 第一个词在 posLenArr 中的位置
+	next            *pathCost // 路径上剩下的部分
 }
 
-func (nodes Nodes) Less(i, j int) bool {
-	if nodes[i].SpaceCount < nodes[j].SpaceCount {
-		return true
-	} else if nodes[i].SpaceCount > nodes[j].SpaceCount {
-		return false
-	} else {
-		if nodes[i].AboveCount < nodes[j].AboveCount {
-			return true
-		} else if nodes[i].AboveCount > nodes[j].AboveCount {
-			return false
-		} else {
-			if freqFirst {
-				if nodes[i].FreqSum > nodes[j].FreqSum {
-					return true
-				} else if nodes[i].FreqSum < nodes[j].FreqSum {
-					return false
-				} else {
-					if nodes[i].SingleWordCount < nodes[j].SingleWordCount {
-						return true
-					} else if nodes[i].SingleWordCount > nodes[j].SingleWordCount {
-						return false
-					}
-				}
-			} else {
-				if nodes[i].SingleWordCount < nodes[j].SingleWordCount {
-					return true
-				} else if nodes[i].SingleWordCount > nodes[j].SingleWordCount {
-					return false
-				} else {
-					if nodes[i].FreqSum > nodes[j].FreqSum {
-						return true
-					} else if nodes[i].FreqSum < nodes[j].FreqSum {
-						return false
-					}
-				}
-			}
-		}
+// 未覆盖的字数少的优先，其次词数少的优先，再次单字少的优先（词频优先时先比较词频之和），
+// 最后词频之和大的优先；指标完全相同时返回 false
+func (a *pathCost) less(b *pathCost) bool {
+	if a.spaceCount != b.spaceCount {
+		return a.spaceCount < b.spaceCount
 	}
-	return true
-}
-
-func (nodes Nodes) Swap(i, j int) {
-	nodes[i], nodes[j] = nodes[j], nodes[i]
+	if a.aboveCount != b.aboveCount {
+		return a.aboveCount < b.aboveCount
+	}
+	if freqFirst && a.freqSum != b.freqSum {
+		return a.freqSum > b.freqSum
+	}
+	if a.singleWordCount != b.singleWordCount {
+		return a.singleWordCount < b.singleWordCount
+	}
+	return a.freqSum > b.freqSum
 }
 
 func NewNode() *Node {
@@ -575,6 +695,7 @@ func NewNode() *Node {
 func NewNodeClone(node *Node) (newNode *Node) {
 	newNode = &Node{}
 	newNode.AboveCount = node.AboveCount
+	newNode.Penalty = node.Penalty
 	newNode.SpaceCount = node.SpaceCount
 	newNode.FreqSum = node.FreqSum
 	newNode.SingleWordCount = node.SingleWordCount
@@ -593,43 +714,3 @@ func NewNodeFull(pl dict.PositionLength, parent *Node, aboveCount int, spaceCoun
 	node.FreqSum = freqSum
 	return
 }
-
-func (m *ChsFullTextMatch) buildTree(parent *Node, curIndex int) {
-	// 嵌套太多的情况一般很少发生，如果发生，强行中断，
-	//以免造成博弈树遍历层次过多降低系统效率
-	if len(m.leafNodeList) > 8192 {
-		return
-	}
- 
-	if curIndex < len(m.posLenArr)-1 {
-		if m.posLenArr[curIndex+1].Position == m.posLenArr[curIndex].Position {
-			m.buildTree(parent, curIndex+1)
-		}
-	}
-
-	spaceCount := parent.SpaceCount + m.posLenArr[curIndex].Position - (parent.PosLen.Position + parent.PosLen.Length)
-	singleWordCount := parent.SingleWordCount
-	if m.posLenArr[curIndex].Length == 1 {
-		singleWordCount += 1
-	}
-
-	freqSum := 0.0
-	if m.options != nil && m.options.FrequencyFirst {
-		freqSum = parent.FreqSum + m.posLenArr[curIndex].WordAttri.Frequency
-	}
-
-	curNode := NewNodeFull(m.posLenArr[curIndex], parent, parent.AboveCount+1, spaceCount, singleWordCount, freqSum)
-	cur := curIndex + 1
-	for cur < len(m.posLenArr) {
-		if m.posLenArr[cur].Position >= m.posLenArr[curIndex].Position+m.posLenArr[curIndex].Length {
-			m.buildTree(curNode, cur)
-			break
-		}
-		cur++
-	}
-
-	if cur >= len(m.posLenArr) {
-		curNode.SpaceCount += m.inputStringLen - curNode.PosLen.Position - curNode.PosLen.Length
-		m.leafNodeList = append(m.leafNodeList, curNode)
-	}
-}
@@ -0,0 +1,274 @@
+package match
+
+import (
+	"math/rand"
+	"segment/dict"
+	"segment/utils"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testDictPath = "../../../bin/dicts"
+
+var testDict *dict.WordDictionary
+var testWords []string
+
+func loadTestDict(t *testing.T) *dict.WordDictionary {
+	if testDict != nil {
+		return testDict
+	}
+	d := dict.NewWordDictionary()
+	if err := d.Load(testDictPath + "/Dict.txt"); err != nil {
+		t.Skip("dictionary not available: ", err)
+	}
+	utils.EachLine(testDictPath+"/Dict.txt", func(line string) {
+		words := strings.Split(strings.TrimPrefix(line, "\ufeff"), "|")
+		if len(words) == 3 && utils.RuneLen(words[0]) <= 4 {
+			testWords = append(testWords, words[0])
+		}
+	})
+	testDict = d
+	return d
+}
+
+// 原来的实现：遍历所有切分组成的树，得到所有叶子节点后用 sort.Sort 排序，
+// 只在测试中作为对照
+type referenceTree struct {
+	frequencyFirst bool
+	posLenArr      []dict.PositionLength
+	inputStringLen int
+	leafNodeList   []*Node
+}
+
+func (m *referenceTree) buildTree(parent *Node, curIndex int) {
+	if curIndex < len(m.posLenArr)-1 {
+		if m.posLenArr[curIndex+1].Position == m.posLenArr[curIndex].Position {
+			m.buildTree(parent, curIndex+1)
+		}
+	}
+
+	spaceCount := parent.SpaceCount + m.posLenArr[curIndex].Position - (parent.PosLen.Position + parent.PosLen.Length)
+	singleWordCount := parent.SingleWordCount
+	if m.posLenArr[curIndex].Length == 1 {
+		singleWordCount += 1
+	}
+
+	freqSum := 0.0
+	if m.frequencyFirst {
+		freqSum = parent.FreqSum + m.posLenArr[curIndex].WordAttri.Frequency
+	}
+
+	curNode := NewNodeFull(m.posLenArr[curIndex], parent, parent.AboveCount+1, spaceCount, singleWordCount, freqSum)
+	cur := curIndex + 1
+	for cur < len(m.posLenArr) {
+		if m.posLenArr[cur].Position >= m.posLenArr[curIndex].Position+m.posLenArr[curIndex].Length {
+			m.buildTree(curNode, cur)
+			break
+		}
+		cur++
+	}
+
+	if cur >= len(m.posLenArr) {
+		curNode.SpaceCount += m.inputStringLen - curNode.PosLen.Position - curNode.PosLen.Length
+		m.leafNodeList = append(m.leafNodeList, curNode)
+	}
+}
+
+type Nodes [](*Node)
+
+func (nodes Nodes) Len() int {
+	return len(nodes)
+}
+
+func (nodes Nodes) Less(i, j int) bool {
+	if nodes[i].SpaceCount < nodes[j].SpaceCount {
+		return true
+	} else if nodes[i].SpaceCount > nodes[j].SpaceCount {
+		return false
+	} else {
+		if nodes[i].AboveCount < nodes[j].AboveCount {
+			return true
+		} else if nodes[i].AboveCount > nodes[j].AboveCount {
+			return false
+		} else {
+			if freqFirst {
+				if nodes[i].FreqSum > nodes[j].FreqSum {
+					return true
+				} else if nodes[i].FreqSum < nodes[j].FreqSum {
+					return false
+				} else {
+					if nodes[i].SingleWordCount < nodes[j].SingleWordCount {
+						return true
+					} else if nodes[i].SingleWordCount > nodes[j].SingleWordCount {
+						return false
+					}
+				}
+			} else {
+				if nodes[i].SingleWordCount < nodes[j].SingleWordCount {
+					return true
+				} else if nodes[i].SingleWordCount > nodes[j].SingleWordCount {
+					return false
+				} else {
+					if nodes[i].FreqSum > nodes[j].FreqSum {
+						return true
+					} else if nodes[i].FreqSum < nodes[j].FreqSum {
+						return false
+					}
+				}
+			}
+		}
+	}
+	return true
+}
+
+func (nodes Nodes) Swap(i, j int) {
+	nodes[i], nodes[j] = nodes[j], nodes[i]
+}
+
+func sameCost(a *Node, b *Node) bool {
+	return a.SpaceCount == b.SpaceCount && a.AboveCount == b.AboveCount &&
+		a.SingleWordCount == b.SingleWordCount && a.FreqSum == b.FreqSum
+}
+
+func pathString(node *Node, runes []rune) string {
+	words := []string{}
+	for ; node != nil && node.AboveCount > 0; node = node.Parent {
+		words = append([]string{string(runes[node.PosLen.Position:(node.PosLen.Position + node.PosLen.Length)])}, words...)
+	}
+	return strings.Join(words, "/")
+}
+
+// 随机拼接词典中的词，有时只取词的第一个字，造出各种有歧义的句子
+func testTexts(count int) []string {
+	texts := []string{
+		"长春市长春节讲话", "结婚的和尚未结婚的", "研究生命起源", "第三十届奥运会",
+		"中华人民共和国成立了", "乒乓球拍卖完了", "他说的确实在理", "发展中国家兔",
+	}
+	r := rand.New(rand.NewSource(1))
+	for len(texts) < count {
+		text := ""
+		for n := 1 + r.Intn(8); n > 0; n-- {
+			word := testWords[r.Intn(len(testWords))]
+			if r.Intn(3) == 0 {
+				word = string(utils.ToRunes(word)[:1])
+			}
+			text += word
+		}
+		texts = append(texts, text)
+	}
+	return texts
+}
+
+// 动态规划得到的前 TopRecord 条路径和原来遍历整棵树再排序的结果比较：
+// 每个名次上的各项指标必须完全相同；路径不同时，只能是原来的结果中有指标完全相同的路径，
+// 原来用不稳定的 sort.Sort 排序，这时的先后顺序是不确定的
+func TestLeafNodeArrayCoreMatchesTreeEnumeration(t *testing.T) {
+	d := loadTestDict(t)
+	m := NewChsFullTextMatch(d)
+	compared := 0
+	for _, text := range testTexts(3000) {
+		runes := utils.ToRunes(text)
+		posLenArr := d.GetAllMatchs(text, false)
+		if len(posLenArr) == 0 {
+			continue
+		}
+		for _, frequencyFirst := range []bool{false, true} {
+			options := NewMatchOptions()
+			options.FrequencyFirst = frequencyFirst
+			m.SetOptionParams(options, NewMatchParameter())
+			freqFirst = frequencyFirst
+
+			ref := &referenceTree{frequencyFirst: frequencyFirst, posLenArr: posLenArr, inputStringLen: len(runes)}
+			ref.buildTree(NewNode(), 0)
+			if len(ref.leafNodeList) > 8192 {
+				continue
+			}
+			sort.Sort(Nodes(ref.leafNodeList))
+			expected := ref.leafNodeList
+			actual := m.getLeafNodeArrayCore(posLenArr, len(runes), TopRecord)
+
+			if len(actual) != utils.IntMin(TopRecord, len(expected)) {
+				t.Fatalf("%s: got %d paths, want %d", text, len(actual), utils.IntMin(TopRecord, len(expected)))
+			}
+			for i, node := range actual {
+				if !sameCost(node, expected[i]) {
+					t.Fatalf("%s (FrequencyFirst=%v) #%d: got %s %+v, want %s %+v", text, frequencyFirst, i,
+						pathString(node, runes), *node, pathString(expected[i], runes), *expected[i])
+				}
+				if pathString(node, runes) == pathString(expected[i], runes) {
+					continue
+				}
+				ties := 0
+				for _, leaf := range expected {
+					if sameCost(leaf, node) {
+						ties++
+					}
+				}
+				if ties < 2 {
+					t.Fatalf("%s (FrequencyFirst=%v) #%d: got %s, want %s", text, frequencyFirst, i,
+						pathString(node, runes), pathString(expected[i], runes))
+				}
+			}
+			compared++
+		}
+	}
+	if compared < 5000 {
+		t.Fatalf("only %d texts compared", compared)
+	}
+}
+
+// 未覆盖的字数、词数和单字数都相同的路径，词频之和大的优先
+func TestLeafNodeArrayCoreTieOrder(t *testing.T) {
+	d := loadTestDict(t)
+	m := NewChsFullTextMatch(d)
+	m.SetOptionParams(NewMatchOptions(), NewMatchParameter())
+	freqFirst = false
+
+	text := "第三十"
+	posLenArr := d.GetAllMatchs(text, false)
+	leafNodeArray := m.getLeafNodeArrayCore(posLenArr, utils.RuneLen(text), TopRecord)
+	if len(leafNodeArray) != 3 || !sameCost(leafNodeArray[1], leafNodeArray[2]) {
+		t.Fatalf("%s: expected the 2nd and 3rd paths to have the same cost", text)
+	}
+	got := []string{}
+	for _, node := range leafNodeArray[1:] {
+		got = append(got, pathString(node, utils.ToRunes(text)))
+	}
+	if got[0] != "第三/十" || got[1] != "第/三十" {
+		t.Fatalf("%s: got %v", text, got)
+	}
+}
+
+// 猜测出来的词按词数加上 Penalty 排序，节点上记录的词数仍然是实际的词数
+func TestLeafNodeArrayCorePenalty(t *testing.T) {
+	d := loadTestDict(t)
+	m := NewChsFullTextMatch(d)
+	m.SetOptionParams(NewMatchOptions(), NewMatchParameter())
+	freqFirst = false
+
+	text := "第三十"
+	posLenArr := d.GetAllMatchs(text, false)
+	for i, pl := range posLenArr {
+		if pl.Length == 3 {
+			wa := *pl.WordAttri
+			wa.Penalty = 2
+			posLenArr[i].WordAttri = &wa
+		}
+	}
+	leafNodeArray := m.getLeafNodeArrayCore(posLenArr, utils.RuneLen(text), TopRecord)
+	got := []string{}
+	for i, node := range leafNodeArray {
+		got = append(got, pathString(node, utils.ToRunes(text)))
+		if i > 0 {
+			prev := leafNodeArray[i-1]
+			if prev.AboveCount+prev.Penalty > node.AboveCount+node.Penalty {
+				t.Errorf("%s: path %d has a smaller penalized word count than path %d", text, i, i-1)
+			}
+		}
+	}
+	last := leafNodeArray[len(leafNodeArray)-1]
+	if len(leafNodeArray) != 3 || got[2] != "第三十" || last.AboveCount != 1 || last.Penalty != 2 {
+		t.Fatalf("%s: got %v, last path AboveCount=%d Penalty=%d", text, got, last.AboveCount, last.Penalty)
+	}
+}
@@ -0,0 +1,79 @@
+package match
+
+import (
+	"segment/dict"
+	"segment/hmm"
+	"segment/utils"
+)
+
+const UnknownWordModelFileName = "UnknownWordHmm.txt"
+
+// BMES 字标注：词首、词中、词尾、单字词
+const (
+	TagBegin  = "B"
+	TagMiddle = "M"
+	TagEnd    = "E"
+	TagSingle = "S"
+)
+
+// 把词转换成 BMES 字标注序列
+func WordToCharTags(word string) (chars []string, tags []string) {
+	runes := utils.ToRunes(word)
+	for i, r := range runes {
+		chars = append(chars, string(r))
+		switch {
+		case len(runes) == 1:
+			tags = append(tags, TagSingle)
+		case i == 0:
+			tags = append(tags, TagBegin)
+		case i == len(runes)-1:
+			tags = append(tags, TagEnd)
+		default:
+			tags = append(tags, TagMiddle)
+		}
+	}
+	return
+}
+
+// 从分好词的语料训练未登录词识别用的 BMES 字标注模型，语料格式见 dict.ParseCorpusLine
+func TrainUnknownWordModel(corpusFile string) (*hmm.Model, error) {
+	trainer := hmm.NewTrainer()
+	err := dict.EachCorpusLine(corpusFile, func(words []string, tags []string) {
+		chars := []string{}
+		charTags := []string{}
+		for _, word := range words {
+			c, t := WordToCharTags(word)
+			chars = append(chars, c...)
+			charTags = append(charTags, t...)
+		}
+		trainer.Add(chars, charTags)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trainer.Model(), nil
+}
+
+// 用 BMES 字标注模型切分 text，返回每个词的长度
+func hmmCut(model *hmm.Model, text []rune) []int {
+	chars := make([]string, len(text))
+	for i, r := range text {
+		chars[i] = string(r)
+	}
+	tags := model.Viterbi(chars, nil)
+
+	lengths := []int{}
+	begin := 0
+	for i, tag := range tags {
+		if (tag == TagBegin || tag == TagSingle) && i > begin {
+			// 不合法的序列，在新词首处断开
+			lengths = append(lengths, i-begin)
+			begin = i
+		}
+		if tag == TagEnd || tag == TagSingle || i == len(tags)-1 {
+			lengths = append(lengths, i+1-begin)
+			begin = i + 1
+		}
+	}
+	return lengths
+}
@@ -2,6 +2,10 @@ package match
 
 type MatchOptions struct {
 	ChineseNameIdentify        bool // 中文人名识别
+	ForeignNameIdentify        bool // 外国人名识别，识别音译人名（包括用 · 分隔的全名）和日本人名
+	ChineseNameFourChars       bool // 四字人名：两个单姓加双名，如 范徐丽泰（复姓加双名由中文人名识别处理）
+	ChineseNamePrefix          bool // 带前缀的人名：老/小 加姓，阿 加姓或名，如 老王、小李、阿强
+	ChineseNameTitle           bool // 姓加称谓，如 王先生、李总
 	FrequencyFirst             bool // 词频优先
 	MultiDimensionality        bool // 多元分词
 	EnglishMultiDimensionality bool // 英文多元分词，这个开关，会将英文中的字母和数字分开
@@ -10,14 +14,39 @@ type MatchOptions struct {
 	ForceSingleWord            bool // 强制一元分词
 	// TraditionalChineseEnabled   bool // 繁体中文开关
 	// OutputSimplifiedTraditional bool // 同时输出简体和繁体
-	UnknownWordIdentify bool // 未登录词识别
-	FilterEnglish       bool // 过滤英文，这个选项只有在过滤停用词选项生效时才有效
-	FilterNumeric       bool // 过滤数字，这个选项只有在过滤停用词选项生效时才有效
-	IgnoreCapital       bool // 忽略英文大小写
-	EnglishSegment      bool // 英文分词
-	SynonymOutput       bool // 同义词输出功能一般用于对搜索字符串的分词，不建议在索引时使用
-	WildcardOutput      bool // 通配符匹配输出
-	WildcardSegment     bool // 对通配符匹配的结果分词
+	UnknownWordIdentify  bool // 未登录词识别
+	FilterEnglish        bool // 过滤英文，这个选项只有在过滤停用词选项生效时才有效
+	FilterNumeric        bool // 过滤数字，这个选项只有在过滤停用词选项生效时才有效
+	IgnoreCapital        bool // 忽略英文大小写
+	EnglishSegment       bool // 英文分词
+	SynonymOutput        bool // 同义词输出功能一般用于对搜索字符串的分词，不建议在索引时使用
+	WildcardOutput       bool // 通配符匹配输出
+	WildcardSegment      bool // 对通配符匹配的结果分词
+	EmojiAnnotation      bool // 输出表情符号的简短名称
+	AccentFolding        bool // 英文去掉重音符号后作为附加的词输出，如 café => cafe
+	NormalizeWidth       bool // 分词前归一化全角半角及兼容字符(NFKC)，输出的位置仍然对应原文
+	NormalizePunctuation bool // 分词前把中文标点、引号、破折号、特殊空白统一成 ASCII 形式
+	NormalizeVariant     bool // 分词前按异体字表把异体字替换成正字
+	NormalizeCase        bool // 分词前对整个文本做大小写折叠
+	MaxProbability       bool // 最大概率分词，按词频(和二元转移表)计算概率最大的路径，代替默认的全文匹配算法
+	HmmUnknownWord       bool // 未登录词用 HMM 字标注模型切分，只有在未登录词识别选项生效且加载了模型时才有效
+	IndexMode            bool // 索引模式，在选出的词后面再输出它包含的所有词典中的词，如 中华人民共和国 => 中华 人民 共和国 ...
+	QueryMode            bool // 查询模式，只输出最优切分中的词，相邻的词正好组成词典中的词时合并，输出的词互不重叠，和索引模式配合使用
+	PosTagging           bool // 分词后用 HMM 词性标注模型根据上下文给每个词标注唯一的词性，只有在加载了模型时才有效
+	PlaceNameIdentify    bool // 地名识别，根据地名表和 省/市/县/区/镇/乡/村/路/街 等后缀识别词典中没有的地名
+	OrgNameIdentify      bool // 机构名识别，识别以专名开头、以 公司/集团/大学/医院/银行 等后缀结尾的机构名
+
+	// 中文匹配算法: fulltext, maxprob, forward, backward, bidirectional 或用 RegisterMatcher 注册的名字，
+	// 为空时按 MaxProbability 选择全文匹配或最大概率分词
+	Matcher string
+
+	IncludePos int // 只保留可以是这些词性的词，为 0 时不过滤，可以用 dict.ParsePosTags 从标注符号得到，如 n|v
+	ExcludePos int // 去掉可以是这些词性的词，如 u|w 去掉助词和标点符号
+
+	// 以下选项只有在过滤停用词选项生效时才有效
+	StopWordLists  []string // 使用的停用词表的名字，如 default、zh、en、punct，为空时只使用默认的 Stopword.txt，没有加载的表被忽略
+	ExtraStopWords []string // 本次分词额外的停用词
+	StopPos        int      // 只能是这些词性的词也作为停用词，如 u|w；和 ExcludePos 不同，兼有其他词性的词不会被去掉
 }
 
 func NewMatchOptions() *MatchOptions {
@@ -17,8 +17,15 @@ type MatchParameter struct {
 	WildcardRank        int // 通配符匹配结果的权值
 	FilterEnglishLength int // 过滤英文选项生效时，过滤大于这个长度的英文。
 	FilterNumericLength int // 过滤数字选项生效时，过滤大于这个长度的数字。
+	EmojiRank           int // 表情符号的权值
+	KanaRank            int // 日文假名的权值
+	HangulRank          int // 韩文的权值
+	AccentFoldingRank   int // 英文词汇去掉重音符号后的权值
+	IndexSubWordRank    int // 索引模式下输出的被包含词的权值，每多嵌套一层减一，最小为 1
+
+	ChineseNameThreshold int // 人名识别的得分阈值（百分制），得分低于阈值的人名不作为候选词，0 表示不过滤
 }
 
 func NewMatchParameter() *MatchParameter {
-	return &MatchParameter{Redundancy: 0, UnknowRank: 1, BestRank: 5, SecRank: 3, ThirdRank: 2, SingleRank: 1, NumericRank: 1, EnglishRank: 5, EnglishLowerRank: 3, EnglishStemRank: 2, SymbolRank: 1, SynonymRank: 1, WildcardRank: 1}
+	return &MatchParameter{Redundancy: 0, UnknowRank: 1, BestRank: 5, SecRank: 3, ThirdRank: 2, SingleRank: 1, NumericRank: 1, EnglishRank: 5, EnglishLowerRank: 3, EnglishStemRank: 2, SymbolRank: 1, SynonymRank: 1, WildcardRank: 1, EmojiRank: 1, KanaRank: 5, HangulRank: 5, AccentFoldingRank: 3, IndexSubWordRank: 4}
 }
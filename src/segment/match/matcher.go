@@ -0,0 +1,104 @@
+package match
+
+import (
+	"fmt"
+	"segment/dict"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// 内置的中文匹配器
+const (
+	FullTextMatcher      = "fulltext"
+	MaxProbMatcher       = "maxprob"
+	ForwardMatcher       = "forward"
+	BackwardMatcher      = "backward"
+	BidirectionalMatcher = "bidirectional"
+)
+
+// 创建匹配器，每次分词都会创建新的匹配器
+type MatcherFactory func(wdict *dict.WordDictionary) IChsFullTextMatch
+
+var (
+	matchersLock sync.RWMutex
+	matchers     = map[string]MatcherFactory{
+		FullTextMatcher: func(wdict *dict.WordDictionary) IChsFullTextMatch {
+			return NewChsFullTextMatch(wdict)
+		},
+		MaxProbMatcher: func(wdict *dict.WordDictionary) IChsFullTextMatch {
+			return NewMaxProbMatch(wdict)
+		},
+		ForwardMatcher: func(wdict *dict.WordDictionary) IChsFullTextMatch {
+			return NewMaxMatch(wdict, ForwardMaxMatch)
+		},
+		BackwardMatcher: func(wdict *dict.WordDictionary) IChsFullTextMatch {
+			return NewMaxMatch(wdict, BackwardMaxMatch)
+		},
+		BidirectionalMatcher: func(wdict *dict.WordDictionary) IChsFullTextMatch {
+			return NewMaxMatch(wdict, BidirectionalMaxMatch)
+		},
+	}
+)
+
+// 注册匹配器，之后可以通过 MatchOptions.Matcher 选择，同名的匹配器会被替换
+func RegisterMatcher(name string, factory MatcherFactory) {
+	matchersLock.Lock()
+	defer matchersLock.Unlock()
+	matchers[name] = factory
+}
+
+// 已经注册的匹配器是否存在
+func HasMatcher(name string) bool {
+	matchersLock.RLock()
+	defer matchersLock.RUnlock()
+	_, ok := matchers[name]
+	return ok
+}
+
+// 已经注册的匹配器的名字，按字母排序
+func MatcherNames() []string {
+	matchersLock.RLock()
+	defer matchersLock.RUnlock()
+	names := []string{}
+	for name := range matchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// options 选择的匹配器，没有注册时返回错误
+func LookupMatcher(options *MatchOptions) (MatcherFactory, error) {
+	name := MatcherName(options)
+	matchersLock.RLock()
+	factory, ok := matchers[name]
+	matchersLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("match: unknown matcher %s (available: %s)", name, strings.Join(MatcherNames(), ", "))
+	}
+	return factory, nil
+}
+
+// 按 options 选择匹配器并设置好参数，选择的匹配器没有注册时返回错误
+func NewMatcher(wdict *dict.WordDictionary, options *MatchOptions, params *MatchParameter) (IChsFullTextMatch, error) {
+	factory, err := LookupMatcher(options)
+	if err != nil {
+		return nil, err
+	}
+	m := factory(wdict)
+	m.SetOptionParams(options, params)
+	return m, nil
+}
+
+// options 选择的匹配器的名字。Matcher 为空时按 MaxProbability 选择
+func MatcherName(options *MatchOptions) string {
+	name := options.Matcher
+	if name == "" {
+		name = FullTextMatcher
+		if options.MaxProbability {
+			name = MaxProbMatcher
+		}
+	}
+	return name
+}
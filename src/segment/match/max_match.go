@@ -0,0 +1,121 @@
+package match
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/utils"
+)
+
+// 最大匹配的方向
+const (
+	ForwardMaxMatch       = 1 // 正向最大匹配，从前往后每次取最长的词
+	BackwardMaxMatch      = 2 // 逆向最大匹配，从后往前每次取最长的词
+	BidirectionalMaxMatch = 3 // 双向最大匹配，取正向和逆向中词数少的，词数相同时取单字少的，再相同时取逆向的
+)
+
+// 最大匹配分词，速度快，不处理歧义，适合对准确率要求不高的场合
+type MaxMatch struct {
+	options   *MatchOptions
+	params    *MatchParameter
+	wordDict  *dict.WordDictionary
+	direction int
+}
+
+func NewMaxMatch(wdict *dict.WordDictionary, direction int) *MaxMatch {
+	return &MaxMatch{wordDict: wdict, direction: direction}
+}
+
+func (m *MaxMatch) SetOptionParams(options *MatchOptions, params *MatchParameter) {
+	m.options = options
+	m.params = params
+}
+
+func (m *MaxMatch) Match(posLenArr []dict.PositionLength, originalText string) *list.List {
+	if m.options == nil {
+		m.options = NewMatchOptions()
+	}
+	if m.params == nil {
+		m.params = NewMatchParameter()
+	}
+	runes := utils.ToRunes(originalText)
+	if len(runes) == 0 {
+		return list.New()
+	}
+
+	var path []pathWord
+	switch m.direction {
+	case BackwardMaxMatch:
+		path = backwardMaxMatch(posLenArr, runes)
+	case BidirectionalMaxMatch:
+		path = forwardMaxMatch(posLenArr, runes)
+		backward := backwardMaxMatch(posLenArr, runes)
+		if fw, bw := len(path), len(backward); bw < fw || (bw == fw && singleWordCount(backward) <= singleWordCount(path)) {
+			path = backward
+		}
+	default:
+		path = forwardMaxMatch(posLenArr, runes)
+	}
+	return outputPath(path, runes, m.options, m.params, m.wordDict)
+}
+
+func forwardMaxMatch(posLenArr []dict.PositionLength, runes []rune) []pathWord {
+	longest := make([]int, len(runes))
+	for i, pl := range posLenArr {
+		if pl.Position+pl.Length <= len(runes) && (longest[pl.Position] == 0 || pl.Length > posLenArr[longest[pl.Position]-1].Length) {
+			longest[pl.Position] = i + 1
+		}
+	}
+
+	path := []pathWord{}
+	for pos := 0; pos < len(runes); {
+		if longest[pos] == 0 {
+			path = append(path, unknownPathWord(runes, pos))
+			pos++
+			continue
+		}
+		pl := posLenArr[longest[pos]-1]
+		path = append(path, pathWord{pl, true})
+		pos += pl.Length
+	}
+	return path
+}
+
+func backwardMaxMatch(posLenArr []dict.PositionLength, runes []rune) []pathWord {
+	longest := make([]int, len(runes)+1)
+	for i, pl := range posLenArr {
+		end := pl.Position + pl.Length
+		if end <= len(runes) && (longest[end] == 0 || pl.Length > posLenArr[longest[end]-1].Length) {
+			longest[end] = i + 1
+		}
+	}
+
+	path := []pathWord{}
+	for end := len(runes); end > 0; {
+		if longest[end] == 0 {
+			path = append(path, unknownPathWord(runes, end-1))
+			end--
+			continue
+		}
+		pl := posLenArr[longest[end]-1]
+		path = append(path, pathWord{pl, true})
+		end -= pl.Length
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func unknownPathWord(runes []rune, pos int) pathWord {
+	return pathWord{dict.NewPositionLength(pos, 1, dict.NewWordAttr(string(runes[pos]), dict.POS_UNK, 0)), false}
+}
+
+func singleWordCount(path []pathWord) int {
+	count := 0
+	for _, w := range path {
+		if w.pl.Length == 1 {
+			count++
+		}
+	}
+	return count
+}
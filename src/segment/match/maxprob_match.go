@@ -0,0 +1,151 @@
+package match
+
+import (
+	"container/list"
+	"math"
+	"segment/dict"
+	"segment/utils"
+)
+
+// 二元转移概率和一元概率插值时二元概率的比重
+const BigramLambda float64 = 0.5
+
+// 最大概率分词：用 GetAllMatchs 的结果构造有向无环图，每个字都补上单字边，
+// 按词频计算每条边的对数概率，动态规划找出概率最大的路径。
+// 词典加载了二元转移表时，边的概率用二元概率和一元概率插值。
+type MaxProbMatch struct {
+	options  *MatchOptions
+	params   *MatchParameter
+	wordDict *dict.WordDictionary
+	logTotal float64
+}
+
+type dagEdge struct {
+	pl     dict.PositionLength
+	known  bool    // 是否是词典中的词
+	logP   float64 // 一元对数概率
+	score  float64 // 到这条边为止的最大对数概率
+	parent int     // 最优路径上的前一条边，-1 表示没有
+}
+
+func NewMaxProbMatch(wdict *dict.WordDictionary) *MaxProbMatch {
+	m := &MaxProbMatch{wordDict: wdict}
+	m.logTotal = math.Log(math.Max(wdict.TotalFrequency(), 1))
+	return m
+}
+
+func (m *MaxProbMatch) SetOptionParams(options *MatchOptions, params *MatchParameter) {
+	m.options = options
+	m.params = params
+}
+
+func (m *MaxProbMatch) Match(posLenArr []dict.PositionLength, originalText string) *list.List {
+	if m.options == nil {
+		m.options = NewMatchOptions()
+	}
+	if m.params == nil {
+		m.params = NewMatchParameter()
+	}
+	runes := utils.ToRunes(originalText)
+	if len(runes) == 0 {
+		return list.New()
+	}
+
+	edges, ending := m.buildDag(posLenArr, runes)
+	path := []pathWord{}
+	for _, i := range m.bestPath(edges, ending, len(runes)) {
+		path = append(path, pathWord{edges[i].pl, edges[i].known})
+	}
+	return outputPath(path, runes, m.options, m.params, m.wordDict)
+}
+
+// 返回所有的边，以及以每个位置结束的边的序号
+func (m *MaxProbMatch) buildDag(posLenArr []dict.PositionLength, runes []rune) (edges []dagEdge, ending [][]int) {
+	edges = make([]dagEdge, 0, len(posLenArr)+len(runes))
+	ending = make([][]int, len(runes)+1)
+	hasSingle := make([]bool, len(runes))
+
+	for _, pl := range posLenArr {
+		if pl.Position+pl.Length > len(runes) {
+			continue
+		}
+		if pl.Length == 1 {
+			hasSingle[pl.Position] = true
+		}
+		edges = append(edges, dagEdge{pl: pl, known: true, logP: m.logProb(pl.WordAttri.Frequency), parent: -1})
+	}
+
+	// 不在词典中的单字，按词频为 1 计算
+	for i, r := range runes {
+		if !hasSingle[i] {
+			pl := dict.NewPositionLength(i, 1, dict.NewWordAttr(string(r), dict.POS_UNK, 0))
+			edges = append(edges, dagEdge{pl: pl, known: false, logP: m.logProb(0), parent: -1})
+		}
+	}
+
+	for i := range edges {
+		end := edges[i].pl.Position + edges[i].pl.Length
+		ending[end] = append(ending[end], i)
+	}
+	return
+}
+
+func (m *MaxProbMatch) bestPath(edges []dagEdge, ending [][]int, length int) []int {
+	// 按起始位置从前往后计算，保证计算一条边时，所有以它的起点结束的边都已经算好
+	starting := make([][]int, length)
+	for i := range edges {
+		starting[edges[i].pl.Position] = append(starting[edges[i].pl.Position], i)
+	}
+
+	useBigram := m.wordDict.Bigram != nil && m.wordDict.Bigram.Len() > 0
+	for pos := 0; pos < length; pos++ {
+		for _, i := range starting[pos] {
+			e := &edges[i]
+			if pos == 0 {
+				e.score = e.logP
+				continue
+			}
+			e.score = math.Inf(-1)
+			for _, j := range ending[pos] {
+				trans := e.logP
+				if useBigram {
+					trans = m.transProb(edges[j], *e)
+				}
+				if score := edges[j].score + trans; score > e.score {
+					e.score = score
+					e.parent = j
+				}
+			}
+		}
+	}
+
+	best := -1
+	for _, i := range ending[length] {
+		if best < 0 || edges[i].score > edges[best].score {
+			best = i
+		}
+	}
+
+	path := []int{}
+	for i := best; i >= 0; i = edges[i].parent {
+		path = append(path, i)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func (m *MaxProbMatch) logProb(frequency float64) float64 {
+	return math.Log(math.Max(frequency, 1)) - m.logTotal
+}
+
+// P(word|prev) = λ * C(prev, word) / C(prev) + (1 - λ) * P(word)
+func (m *MaxProbMatch) transProb(prev dagEdge, e dagEdge) float64 {
+	count := m.wordDict.Bigram.Count(prev.pl.WordAttri.Word, e.pl.WordAttri.Word)
+	if count == 0 {
+		return math.Log(1-BigramLambda) + e.logP
+	}
+	p := BigramLambda*count/math.Max(prev.pl.WordAttri.Frequency, count) + (1-BigramLambda)*math.Exp(e.logP)
+	return math.Log(p)
+}
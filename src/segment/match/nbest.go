@@ -0,0 +1,200 @@
+package match
+
+import (
+	"container/list"
+	"math"
+	"segment/dict"
+	"segment/utils"
+	"sort"
+)
+
+// 一种完整的切分结果和它的各项指标，排序规则和全文匹配选择最优切分的规则相同：
+// 未覆盖的字数少的优先，其次词数（加上 Penalty）少的优先，再次单字少的优先（词频优先时先比较词频之和），
+// 最后词频之和大的优先
+type Segmentation struct {
+	Words           *list.List // *dict.WordInfo，没有被词典中的词覆盖的字作为未登录词输出
+	SpaceCount      int        // 没有被词典中的词覆盖的字数
+	WordCount       int        // 词典中的词的个数
+	SingleWordCount int        // 单字词的个数
+	Penalty         int        // 猜测出来的词额外计算的词数，见 dict.WordAttr.Penalty
+	FreqSum         float64    // 词频之和
+	LogProb         float64    // 按词频计算的一元对数概率，未登录的字按词频为 1 计算
+	Confidence      float64    // 由 LogProb 换算出的在所有返回结果中的可信度，总和为 1
+}
+
+// 和 pathCost.less 相同
+func (a *Segmentation) less(b *Segmentation, frequencyFirst bool) bool {
+	if a.SpaceCount != b.SpaceCount {
+		return a.SpaceCount < b.SpaceCount
+	}
+	if a.WordCount+a.Penalty != b.WordCount+b.Penalty {
+		return a.WordCount+a.Penalty < b.WordCount+b.Penalty
+	}
+	if frequencyFirst && a.FreqSum != b.FreqSum {
+		return a.FreqSum > b.FreqSum
+	}
+	if a.SingleWordCount != b.SingleWordCount {
+		return a.SingleWordCount < b.SingleWordCount
+	}
+	return a.FreqSum > b.FreqSum
+}
+
+// 能给出多种完整切分的匹配器，内置的匹配器中只有全文匹配支持
+type NBestMatcher interface {
+	NBest(posLenArr []dict.PositionLength, originalText string, n int) []*Segmentation
+}
+
+// 返回 originalText 最好的 n 种完整切分，按排序规则从好到坏排列
+func (m *ChsFullTextMatch) NBest(posLenArr []dict.PositionLength, originalText string, n int) []*Segmentation {
+	if m.options == nil {
+		m.options = NewMatchOptions()
+	}
+	if m.params == nil {
+		m.params = NewMatchParameter()
+	}
+	runes := utils.ToRunes(originalText)
+	if n <= 0 || len(runes) == 0 {
+		return nil
+	}
+	if len(posLenArr) == 0 {
+		seg := m.newSegmentation(nil, runes)
+		seg.Confidence = 1
+		return []*Segmentation{seg}
+	}
+
+	// 孤立点之间的片段互不影响，整个文本一起计算和分段计算的结果相同
+	freqFirst = m.options.FrequencyFirst
+	result := []*Segmentation{}
+	for _, leaf := range m.getLeafNodeArrayCore(posLenArr, len(runes), n) {
+		path := make([]dict.PositionLength, leaf.AboveCount)
+		node := leaf
+		for i := leaf.AboveCount - 1; i >= 0; i-- {
+			path[i] = node.PosLen
+			node = node.Parent
+		}
+		result = append(result, m.newSegmentation(path, runes))
+	}
+	SetConfidence(result)
+	return result
+}
+
+func (m *ChsFullTextMatch) newSegmentation(path []dict.PositionLength, runes []rune) *Segmentation {
+	seg := &Segmentation{Words: list.New()}
+	logTotal := math.Log(math.Max(m.wordDict.TotalFrequency(), 1))
+	pos := 0
+	for i := 0; i <= len(path); i++ {
+		end := len(runes)
+		if i < len(path) {
+			end = path[i].Position
+		}
+		if end > pos {
+			seg.SpaceCount += end - pos
+			seg.LogProb -= float64(end-pos) * logTotal
+			m.pushUnknownWords(seg.Words, runes, pos, end)
+		}
+		if i == len(path) {
+			break
+		}
+
+		pl := path[i]
+		wi := dict.NewWordInfo(string(runes[pl.Position:(pl.Position+pl.Length)]), pl.Position, pl.WordAttri.Pos, pl.WordAttri.Frequency, m.params.BestRank, dict.TSimplifiedChinese, dict.TSimplifiedChinese)
+		wi.Score = pl.WordAttri.Score
+		seg.Words.PushBack(wi)
+		seg.WordCount++
+		seg.Penalty += pl.WordAttri.Penalty
+		if pl.Length == 1 {
+			seg.SingleWordCount++
+		}
+		seg.FreqSum += pl.WordAttri.Frequency
+		seg.LogProb += math.Log(math.Max(pl.WordAttri.Frequency, 1)) - logTotal
+		pos = pl.Position + pl.Length
+	}
+	return seg
+}
+
+// 没有被覆盖的字，打开未登录词识别时连在一起作为一个未登录词，否则逐字输出
+func (m *ChsFullTextMatch) pushUnknownWords(words *list.List, runes []rune, begin int, end int) {
+	for begin < end {
+		l := 1
+		if m.options.UnknownWordIdentify {
+			l = end - begin
+		}
+		wi := dict.NewWordInfoDefault()
+		wi.Word = string(runes[begin:(begin + l)])
+		wi.Position = begin
+		wi.WordType = dict.TNone
+		wi.Rank = m.params.UnknowRank
+		words.PushBack(wi)
+		begin += l
+	}
+}
+
+// 把依次相连的几段文本各自的切分结果组合成整个文本的切分结果，取最好的 n 种。
+// parts 中每一段的结果都必须不为空
+func CombineSegmentations(parts [][]*Segmentation, n int, frequencyFirst bool) []*Segmentation {
+	// 组合的过程中只记录每种结果是哪个前缀接上这一段的哪种切分，前缀是共用的，
+	// 最后才为选出的结果生成词序列
+	type combination struct {
+		total  Segmentation // 到这一段为止的各项指标
+		prefix *combination
+		last   *Segmentation
+	}
+	result := []*combination{&combination{}}
+	for _, part := range parts {
+		combined := []*combination{}
+		for _, a := range result {
+			for _, b := range part {
+				c := &combination{prefix: a, last: b}
+				c.total.SpaceCount = a.total.SpaceCount + b.SpaceCount
+				c.total.WordCount = a.total.WordCount + b.WordCount
+				c.total.SingleWordCount = a.total.SingleWordCount + b.SingleWordCount
+				c.total.Penalty = a.total.Penalty + b.Penalty
+				c.total.FreqSum = a.total.FreqSum + b.FreqSum
+				c.total.LogProb = a.total.LogProb + b.LogProb
+				combined = append(combined, c)
+			}
+		}
+		sort.SliceStable(combined, func(i, j int) bool {
+			return combined[i].total.less(&combined[j].total, frequencyFirst)
+		})
+		if len(combined) > n {
+			combined = combined[:n]
+		}
+		result = combined
+	}
+
+	segs := make([]*Segmentation, len(result))
+	for i, c := range result {
+		seg := c.total
+		seg.Words = list.New()
+		// 每种结果都有自己的 WordInfo，调用者修改一种结果不会影响其他结果
+		for p := c; p.last != nil; p = p.prefix {
+			for cur := p.last.Words.Back(); cur != nil; cur = cur.Prev() {
+				wi := *cur.Value.(*dict.WordInfo)
+				seg.Words.PushFront(&wi)
+			}
+		}
+		segs[i] = &seg
+	}
+	SetConfidence(segs)
+	return segs
+}
+
+// 按 LogProb 计算每种切分在这些结果中的可信度
+func SetConfidence(segs []*Segmentation) {
+	if len(segs) == 0 {
+		return
+	}
+	max := segs[0].LogProb
+	for _, seg := range segs {
+		max = math.Max(max, seg.LogProb)
+	}
+	sum := 0.0
+	for _, seg := range segs {
+		seg.Confidence = math.Exp(seg.LogProb - max)
+		sum += seg.Confidence
+	}
+	for _, seg := range segs {
+		seg.Confidence /= sum
+	}
+}
@@ -0,0 +1,81 @@
+package match
+
+import (
+	"container/list"
+	"segment/dict"
+	"strings"
+	"testing"
+)
+
+func wordsString(words *list.List) string {
+	s := []string{}
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		s = append(s, cur.Value.(*dict.WordInfo).Word)
+	}
+	return strings.Join(s, "/")
+}
+
+// 第一种切分和全文匹配只输出最优切分时的结果相同
+func TestNBestFirstMatchesBestPath(t *testing.T) {
+	d := loadTestDict(t)
+	for _, text := range testTexts(1000) {
+		for _, frequencyFirst := range []bool{false, true} {
+			options := NewMatchOptions()
+			options.FrequencyFirst = frequencyFirst
+			options.MultiDimensionality = false
+			options.UnknownWordIdentify = false
+			params := NewMatchParameter()
+			params.Redundancy = 0
+
+			pls := d.GetAllMatchs(text, false)
+			m := NewChsFullTextMatch(d)
+			m.SetOptionParams(options, params)
+			best := wordsString(m.Match(pls, text))
+
+			m = NewChsFullTextMatch(d)
+			m.SetOptionParams(options, params)
+			segs := m.NBest(pls, text, 3)
+			if got := wordsString(segs[0].Words); got != best {
+				t.Fatalf("%s (FrequencyFirst=%v): got %s, want %s", text, frequencyFirst, got, best)
+			}
+			for i := 1; i < len(segs); i++ {
+				if segs[i].less(segs[i-1], frequencyFirst) {
+					t.Fatalf("%s (FrequencyFirst=%v): %s ranked after %s", text, frequencyFirst, wordsString(segs[i].Words), wordsString(segs[i-1].Words))
+				}
+			}
+		}
+	}
+}
+
+// 分段组合的结果和整段一起计算的结果相同
+func TestCombineSegmentations(t *testing.T) {
+	d := loadTestDict(t)
+	m := NewChsFullTextMatch(d)
+	m.SetOptionParams(NewMatchOptions(), NewMatchParameter())
+	texts := []string{"长春市长", "研究生命", "和尚未"}
+	parts := [][]*Segmentation{}
+	for _, text := range texts {
+		parts = append(parts, m.NBest(d.GetAllMatchs(text, false), text, 3))
+	}
+	segs := CombineSegmentations(parts, 3, false)
+	if len(segs) != 3 {
+		t.Fatalf("got %d segmentations", len(segs))
+	}
+	best := []string{}
+	for _, part := range parts {
+		best = append(best, wordsString(part[0].Words))
+	}
+	if got := wordsString(segs[0].Words); got != strings.Join(best, "/") {
+		t.Fatalf("got %s, want %s", got, strings.Join(best, "/"))
+	}
+	for i := 1; i < len(segs); i++ {
+		if segs[i].less(segs[i-1], false) {
+			t.Fatalf("%s ranked after %s", wordsString(segs[i].Words), wordsString(segs[i-1].Words))
+		}
+	}
+	// 共用前缀时每种结果仍然有自己的 WordInfo
+	segs[0].Words.Front().Value.(*dict.WordInfo).Position = 100
+	if segs[1].Words.Front().Value.(*dict.WordInfo).Position != 0 {
+		t.Fatalf("segmentations share WordInfo")
+	}
+}
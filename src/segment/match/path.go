@@ -0,0 +1,72 @@
+package match
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/utils"
+)
+
+// 匹配器选出的路径上的一个词，known 为 false 表示不在词典中的单字
+type pathWord struct {
+	pl    dict.PositionLength
+	known bool
+}
+
+// 把覆盖整个文本的路径转换成分词结果：连续的未登录单字合并成一个未登录词，或者用 HMM 字标注模型切分，
+// 打开强制一元分词时再补上所有的单字
+func outputPath(path []pathWord, runes []rune, options *MatchOptions, params *MatchParameter, wordDict *dict.WordDictionary) *list.List {
+	result := list.New()
+	for i := 0; i < len(path); i++ {
+		w := path[i]
+		if w.known {
+			wi := dict.NewWordInfo(string(runes[w.pl.Position:(w.pl.Position+w.pl.Length)]), w.pl.Position, w.pl.WordAttri.Pos, w.pl.WordAttri.Frequency, params.BestRank, dict.TSimplifiedChinese, dict.TSimplifiedChinese)
+			wi.Score = w.pl.WordAttri.Score
+			result.PushBack(wi)
+			continue
+		}
+
+		end := i + 1
+		if options.UnknownWordIdentify {
+			for end < len(path) && !path[end].known {
+				end++
+			}
+		}
+		begin := w.pl.Position
+		stop := path[end-1].pl.Position + path[end-1].pl.Length
+		lengths := []int{stop - begin}
+		if options.UnknownWordIdentify && options.HmmUnknownWord && wordDict.UnknownWordModel != nil {
+			lengths = hmmCut(wordDict.UnknownWordModel, runes[begin:stop])
+		}
+		for _, l := range lengths {
+			wi := dict.NewWordInfoDefault()
+			wi.Word = string(runes[begin:(begin + l)])
+			wi.Position = begin
+			wi.WordType = dict.TNone
+			wi.Rank = params.UnknowRank
+			result.PushBack(wi)
+			begin += l
+		}
+		i = end - 1
+	}
+
+	// 强制一元分词
+	if options.ForceSingleWord {
+		cur := result.Front()
+		for i, r := range runes {
+			for cur != nil && cur.Value.(*dict.WordInfo).Position < i {
+				cur = cur.Next()
+			}
+			if cur != nil && cur.Value.(*dict.WordInfo).Position == i && utils.RuneLen(cur.Value.(*dict.WordInfo).Word) == 1 {
+				continue
+			}
+			wi := dict.NewWordInfo(string(r), i, dict.POS_UNK, 0, params.SingleRank, dict.TSimplifiedChinese, dict.TSimplifiedChinese)
+			if cur == nil {
+				result.PushBack(wi)
+			} else {
+				result.InsertBefore(wi, cur)
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,95 @@
+package match
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/utils"
+)
+
+// 一段中文的匹配过程，用于解释分词结果。位置都是相对于这段中文的
+type MatchTrace struct {
+	Offset       int           `json:"offset"` // 这段中文在分词文本中的位置
+	Text         string        `json:"text"`
+	Matcher      string        `json:"matcher"`
+	Candidates   []TraceWord   `json:"candidates"`             // 词典中匹配到的所有词
+	Spans        []TraceSpan   `json:"spans,omitempty"`        // 按孤立点分割的片段，全文匹配才有
+	Combinations [][]TraceWord `json:"combinations,omitempty"` // 选出的前几种组合，全文匹配才有
+	Masks        []int         `json:"masks,omitempty"`        // 未登录词识别用的标记: 0 未覆盖 1 单字 2 多字词 11 被未登录词合并的单字
+	Result       []TraceWord   `json:"result"`                 // 匹配器的输出
+}
+
+type TraceWord struct {
+	Word      string  `json:"word"`
+	Position  int     `json:"position"`
+	Length    int     `json:"length"`
+	Pos       int     `json:"pos"`
+	Tag       string  `json:"tag,omitempty"` // 词性的标注符号
+	Frequency float64 `json:"frequency"`
+	Rank      int     `json:"rank,omitempty"`
+}
+
+// 孤立点之间的一个片段和其中排在最前面的几条路径
+type TraceSpan struct {
+	Position int         `json:"position"`
+	Length   int         `json:"length"`
+	Leaves   []TraceLeaf `json:"leaves"`
+}
+
+type TraceLeaf struct {
+	Words           []TraceWord `json:"words"`
+	SpaceCount      int         `json:"space_count"`
+	AboveCount      int         `json:"above_count"` // 词数
+	Penalty         int         `json:"penalty"`     // 猜测出来的词额外计算的词数，路径按 AboveCount+Penalty 排序
+	SingleWordCount int         `json:"single_word_count"`
+	FreqSum         float64     `json:"freq_sum"`
+}
+
+// 可以记录匹配过程的匹配器
+type Traceable interface {
+	SetTrace(trace *MatchTrace)
+}
+
+func NewMatchTrace(text string, offset int, matcher string, posLenArr []dict.PositionLength) *MatchTrace {
+	t := &MatchTrace{Offset: offset, Text: text, Matcher: matcher}
+	runes := utils.ToRunes(text)
+	for _, pl := range posLenArr {
+		t.Candidates = append(t.Candidates, newTraceWord(pl, runes))
+	}
+	return t
+}
+
+// 记录匹配器的输出
+func (t *MatchTrace) SetResult(words *list.List) {
+	t.Result = []TraceWord{}
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		t.Result = append(t.Result, TraceWord{Word: wi.Word, Position: wi.Position, Length: utils.RuneLen(wi.Word), Pos: wi.Pos, Tag: dict.PosString(wi.Pos), Frequency: wi.Frequency, Rank: wi.Rank})
+	}
+}
+
+func (t *MatchTrace) addSpan(position int, length int, leafNodeArray []*Node, runes []rune) {
+	span := TraceSpan{Position: position, Length: length}
+	for _, leaf := range leafNodeArray {
+		tl := TraceLeaf{SpaceCount: leaf.SpaceCount, AboveCount: leaf.AboveCount, Penalty: leaf.Penalty, SingleWordCount: leaf.SingleWordCount, FreqSum: leaf.FreqSum}
+		tl.Words = make([]TraceWord, leaf.AboveCount)
+		node := leaf
+		for i := leaf.AboveCount - 1; i >= 0; i-- {
+			tl.Words[i] = newTraceWord(node.PosLen, runes)
+			node = node.Parent
+		}
+		span.Leaves = append(span.Leaves, tl)
+	}
+	t.Spans = append(t.Spans, span)
+}
+
+func (t *MatchTrace) addCombination(comb []dict.PositionLength, runes []rune) {
+	words := []TraceWord{}
+	for _, pl := range comb {
+		words = append(words, newTraceWord(pl, runes))
+	}
+	t.Combinations = append(t.Combinations, words)
+}
+
+func newTraceWord(pl dict.PositionLength, runes []rune) TraceWord {
+	return TraceWord{Word: string(runes[pl.Position:(pl.Position + pl.Length)]), Position: pl.Position, Length: pl.Length, Pos: pl.WordAttri.Pos, Tag: dict.PosString(pl.WordAttri.Pos), Frequency: pl.WordAttri.Frequency}
+}
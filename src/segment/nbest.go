@@ -0,0 +1,87 @@
+package segment
+
+import (
+	"container/list"
+	"fmt"
+	"segment/dict"
+	"segment/match"
+)
+
+// 返回 text 最好的 n 种完整切分，每种切分带有排序用的各项指标和可信度，
+// 可以用来对有歧义的查询生成多种读法，如 长春/市长/春节 和 长春市/长春/节。
+// 只有中文部分有多种切分，其他部分和词法分析的结果相同。
+// 选择的匹配器（Matcher、MaxProbability）必须支持 match.NBestMatcher，否则返回错误；
+// 多元分词、强制一元分词、查询模式、索引模式等附加输出，以及停用词、词性过滤和词性标注不起作用
+func (s *Segment) NBestSegment(text string, n int, options *match.MatchOptions, params *match.MatchParameter) ([]*match.Segmentation, error) {
+	if len(text) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	if err := s.setOptionParams(options, params); err != nil {
+		return nil, err
+	}
+	if _, ok := s.newMatcher(s.options).(match.NBestMatcher); !ok {
+		return nil, fmt.Errorf("segment: matcher %s does not support n-best segmentation", match.MatcherName(s.options))
+	}
+
+	var offsets []int
+	if function := s.normalizeFunction(); function != 0 {
+		text, offsets = s.normalizer.Normalize(text, function)
+	}
+
+	parts := [][]*match.Segmentation{}
+	initWords := s.getInitSegment(text)
+	if s.options.ForeignNameIdentify {
+		s.joinForeignNames(initWords)
+	}
+	for cur := initWords.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		switch wi.WordType {
+		case dict.TSpace:
+			if s.options.IgnoreSpace {
+				continue
+			}
+		case dict.TSimplifiedChinese:
+			pls := s.wordDictionary.GetAllMatchs(wi.Word, s.options.ChineseNameIdentify, s.recognizers()...)
+			segs := s.newMatcher(s.options).(match.NBestMatcher).NBest(pls, wi.Word, n)
+			for _, seg := range segs {
+				for w := seg.Words.Front(); w != nil; w = w.Next() {
+					w.Value.(*dict.WordInfo).Position += wi.Position
+				}
+			}
+			parts = append(parts, segs)
+			continue
+		}
+
+		wi.Rank = s.tokenRank(wi.WordType)
+		words := list.New()
+		words.PushBack(wi)
+		parts = append(parts, []*match.Segmentation{&match.Segmentation{Words: words}})
+	}
+
+	result := match.CombineSegmentations(parts, n, s.options.FrequencyFirst)
+	for _, seg := range result {
+		setOriginalLength(seg.Words)
+		if offsets != nil {
+			s.restorePosition(seg.Words, offsets)
+		}
+	}
+	return result, nil
+}
+
+// 词法分析得到的非中文词的权值
+func (s *Segment) tokenRank(wordType int) int {
+	switch wordType {
+	case dict.TEnglish:
+		return s.params.EnglishRank
+	case dict.TNumeric:
+		return s.params.NumericRank
+	case dict.TKana:
+		return s.params.KanaRank
+	case dict.THangul:
+		return s.params.HangulRank
+	case dict.TEmoji:
+		return s.params.EmojiRank
+	}
+	return s.params.SymbolRank
+}
@@ -0,0 +1,45 @@
+package segment
+
+import (
+	"segment/dict"
+	"segment/match"
+	"strings"
+	"testing"
+)
+
+func TestNBestSegment(t *testing.T) {
+	s := loadTestSegment(t)
+	segs, err := s.NBestSegment("长春市长春节讲话", 3, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segs) != 3 {
+		t.Fatalf("got %d segmentations, want 3", len(segs))
+	}
+	words := []string{}
+	for cur := segs[0].Words.Front(); cur != nil; cur = cur.Next() {
+		words = append(words, cur.Value.(*dict.WordInfo).Word)
+	}
+	if got := strings.Join(words, "/"); got != "长春/市长/春节/讲话" {
+		t.Errorf("best segmentation: got %s", got)
+	}
+}
+
+// 不支持多种切分的匹配器和没有注册的匹配器都返回错误
+func TestNBestSegmentMatcher(t *testing.T) {
+	s := loadTestSegment(t)
+	options := match.NewMatchOptions()
+	options.MaxProbability = true
+	if _, err := s.NBestSegment("长春市长春节讲话", 3, options, nil); err == nil {
+		t.Errorf("expected an error for the maxprob matcher")
+	}
+	options = match.NewMatchOptions()
+	options.Matcher = match.ForwardMatcher
+	if _, err := s.NBestSegment("长春市长春节讲话", 3, options, nil); err == nil {
+		t.Errorf("expected an error for the forward matcher")
+	}
+	options.Matcher = "nonexistent"
+	if _, err := s.NBestSegment("长春市长春节讲话", 3, options, nil); err == nil {
+		t.Errorf("expected an error for an unknown matcher")
+	}
+}
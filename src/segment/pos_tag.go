@@ -0,0 +1,54 @@
+package segment
+
+import (
+	"container/list"
+	"os"
+	"segment/dict"
+	"segment/hmm"
+	"segment/utils"
+	"sort"
+)
+
+// 词性标注模型是可选的，可以用 train-pos 命令从人民日报格式的语料训练
+func (s *Segment) loadPosModel(file string) (err error) {
+	if _, err = os.Stat(file); os.IsNotExist(err) {
+		return nil
+	}
+	model := hmm.NewModel()
+	if err = model.Load(file); err == nil {
+		s.posTagger = dict.NewPosTagger(model)
+	}
+	return
+}
+
+// 根据上下文标注词性。多元分词等输出的词会互相重叠，从前往后每个位置取权值最高（其次最长）的词，
+// 组成互不重叠的词序列一起标注，和它们重叠的其他词单独标注
+func (s *Segment) tagPos(wordInfoList *list.List) {
+	words := []*dict.WordInfo{}
+	for cur := wordInfoList.Front(); cur != nil; cur = cur.Next() {
+		if cur.Value.(*dict.WordInfo).WordType != dict.TSpace {
+			words = append(words, cur.Value.(*dict.WordInfo))
+		}
+	}
+	sort.SliceStable(words, func(i, j int) bool {
+		if words[i].Position != words[j].Position {
+			return words[i].Position < words[j].Position
+		}
+		if words[i].Rank != words[j].Rank {
+			return words[i].Rank > words[j].Rank
+		}
+		return utils.RuneLen(words[i].Word) > utils.RuneLen(words[j].Word)
+	})
+
+	sequence := []*dict.WordInfo{}
+	end := 0
+	for _, wi := range words {
+		if wi.Position >= end {
+			sequence = append(sequence, wi)
+			end = wi.Position + utils.RuneLen(wi.Word)
+		} else {
+			s.posTagger.TagWord(wi)
+		}
+	}
+	s.posTagger.Tag(sequence)
+}
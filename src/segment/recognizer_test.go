@@ -0,0 +1,113 @@
+package segment
+
+import (
+	"segment/dict"
+	"segment/match"
+	"strings"
+	"testing"
+)
+
+const testDictPath = "../../bin/dicts"
+
+var testSegment *Segment
+
+func loadTestSegment(t *testing.T) *Segment {
+	if testSegment == nil {
+		s := NewSegment()
+		if err := s.Init(testDictPath); err != nil {
+			t.Skip("dictionary not available: ", err)
+		}
+		testSegment = s
+	}
+	return testSegment
+}
+
+// 分词结果，识别出的专名后面带上词性，如 武汉市/黄陂区(ns)
+func segmentString(s *Segment, text string, options *match.MatchOptions) string {
+	words := []string{}
+	for cur := s.DoSegmentWithOption(text, options).Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		word := wi.Word
+		for _, pos := range []int{dict.POS_A_NS, dict.POS_A_NT, dict.POS_A_NR} {
+			if wi.Pos == pos {
+				word += "(" + dict.PosTagName(pos) + ")"
+			}
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, "/")
+}
+
+func checkSegments(t *testing.T, options *match.MatchOptions, cases map[string]string) {
+	s := loadTestSegment(t)
+	for text, expected := range cases {
+		if got := segmentString(s, text, options); got != expected {
+			t.Errorf("%s: got %s, want %s", text, got, expected)
+		}
+	}
+}
+
+func TestPlaceName(t *testing.T) {
+	options := match.NewMatchOptions()
+	options.PlaceNameIdentify = true
+	checkSegments(t, options, map[string]string{
+		"武汉市黄陂区":       "武汉市(ns)/黄陂区(ns)",
+		"湖北省武汉市黄陂区某某村": "湖北省(ns)/武汉市(ns)/黄陂区(ns)/某某村(ns)",
+		"我住在海淀区中关村大街":  "我/住在/海淀区(ns)/中关村大街(ns)",
+		"黄陂区政府":        "黄陂区(ns)/政府",
+		// 猜测的地名不能含有动词、方位词，不能截断词典中的词
+		"在家里走路":    "在家/里/走路",
+		"他们在城市里走路": "他们/在/城市/里/走路",
+		"我们一起去逛街":  "我们/一起去/逛街",
+		"农贸市场":     "农贸市场",
+	})
+}
+
+func TestOrgName(t *testing.T) {
+	options := match.NewMatchOptions()
+	options.PlaceNameIdentify = true
+	options.OrgNameIdentify = true
+	checkSegments(t, options, map[string]string{
+		"华为技术有限公司":   "华为技术有限公司(nt)",
+		"北京大学附属医院":   "北京大学附属医院(nt)",
+		"中国人民银行上海分行": "中国人民银行上海分行(nt)",
+		"他在鑫源公司上班":   "他/在/鑫源公司(nt)/上班",
+		// 字号不能截断词典中的词，也不能含有动词、量词
+		"我们去医院看病":  "我们/去/医院/看病",
+		"我家门口有个小学": "我家/门口/有/个/小学",
+	})
+}
+
+func TestForeignName(t *testing.T) {
+	options := match.NewMatchOptions()
+	options.ForeignNameIdentify = true
+	checkSegments(t, options, map[string]string{
+		"奥巴马访华":        "奥巴马(nr)/访华",
+		"约翰·菲茨杰拉德·肯尼迪": "约翰·菲茨杰拉德·肯尼迪(nr)",
+		"伊丽莎白·泰勒":      "伊丽莎白·泰勒(nr)",
+		"迈克尔·杰克逊":      "迈克尔·杰克逊(nr)",
+		"山口百惠":         "山口百惠(nr)",
+		// 由常用的音译用字组成的词语不是人名
+		"马拉松比赛":     "马拉松/比赛",
+		"我们去看马拉松比赛": "我们/去/看/马拉松/比赛",
+		"他比赛得多":     "他/比赛/得多",
+	})
+}
+
+func TestChineseNamePattern(t *testing.T) {
+	options := match.NewMatchOptions()
+	options.ChineseNameFourChars = true
+	options.ChineseNamePrefix = true
+	options.ChineseNameTitle = true
+	checkSegments(t, options, map[string]string{
+		"欧阳娜娜说": "欧阳娜娜(nr)/说",
+		"上官婉儿说": "上官婉儿(nr)/说",
+		"陈方安生说": "陈方安生(nr)/说",
+		"李总说":   "李总(nr)/说",
+		"王先生来了": "王先生(nr)/来/了",
+		// 单字的称谓和后面的字组成词时不作为称谓
+		"请在周工作日来": "请/在/周/工作日/来",
+		"钱总是不够用":  "钱/总是/不/够用",
+		"王哥哥来了":   "王/哥哥/来/了",
+	})
+}
@@ -2,8 +2,10 @@ package segment
 
 import (
 	"container/list"
+	"os"
 	"segment/dict"
 	"segment/framework"
+	"segment/hmm"
 	"segment/match"
 	"segment/utils"
 	"strings"
@@ -11,7 +13,7 @@ import (
 	"unicode"
 )
 
-const PATTERNS = `([０-９\d]+)|([ａ-ｚＡ-Ｚa-zA-Z_]+)`
+const PATTERNS = `([０-９\d]+)|([\p{L}\p{M}_]+)`
 
 type Segment struct {
 	options        *match.MatchOptions
@@ -21,7 +23,16 @@ type Segment struct {
 	chsName        *dict.ChsName
 	stopWord       *dict.StopWord
 	synonym        *dict.Synonym
+	emoji          *dict.Emoji
+	normalizer     *framework.Normalizer
 	re             *regexp.Regexp
+	explanation    *Explanation
+	posTagger      *dict.PosTagger
+	placeName      *dict.PlaceName
+	orgName        *dict.OrgName
+	foreignName    *dict.ForeignName
+	namePattern    *dict.ChsNamePattern
+	matcher        match.MatcherFactory
 }
 
 func NewSegment() *Segment {
@@ -34,6 +45,10 @@ func (s *Segment) Init(dictPath string) (err error) {
 	if err == nil {
 		err = s.loadDictionary(dictPath)
 	}
+	if err == nil {
+		s.normalizer = framework.NewNormalizer()
+		err = s.normalizer.LoadVariant(dictPath + "/Variant.txt")
+	}
 	return
 }
 
@@ -64,14 +79,65 @@ func (s *Segment) loadDictionary(dictPath string) (err error) {
 		s.stopWord = dict.NewStopWord()
 		err = s.stopWord.Load(dictPath + "/Stopword.txt")
 	}
+	if err == nil {
+		err = s.stopWord.LoadLists(dictPath)
+	}
 	if err == nil {
 		s.synonym = dict.NewSynonym()
 		err = s.synonym.Load(dictPath)
 	}
+	if err == nil {
+		s.placeName = dict.NewPlaceName(s.wordDictionary)
+		err = s.placeName.Load(dictPath)
+	}
+	if err == nil {
+		s.orgName = dict.NewOrgName(s.wordDictionary, s.placeName)
+		err = s.orgName.Load(dictPath)
+	}
+	if err == nil {
+		s.foreignName = dict.NewForeignName(s.wordDictionary)
+		err = s.foreignName.Load(dictPath)
+	}
+	if err == nil {
+		s.emoji = dict.NewEmoji()
+		err = s.emoji.Load(dictPath)
+	}
+	if err == nil {
+		s.wordDictionary.Bigram = dict.NewBigram()
+		err = s.wordDictionary.Bigram.Load(dictPath)
+	}
+	if err == nil {
+		err = s.loadUnknownWordModel(dictPath + "/" + match.UnknownWordModelFileName)
+	}
+	if err == nil {
+		err = s.loadPosModel(dictPath + "/" + dict.PosModelFileName)
+	}
 	// todo: wildchar & segment cross referrence problem
 	return
 }
 
+// 未登录词识别模型是可选的，可以用 train-hmm 命令从分好词的语料训练
+func (s *Segment) loadUnknownWordModel(file string) (err error) {
+	if _, err = os.Stat(file); os.IsNotExist(err) {
+		return nil
+	}
+	model := hmm.NewModel()
+	if err = model.Load(file); err == nil {
+		s.wordDictionary.UnknownWordModel = model
+	}
+	return
+}
+
+// 分词使用的词典
+func (s *Segment) WordDictionary() *dict.WordDictionary {
+	return s.wordDictionary
+}
+
+// 已经加载的停用词表的名字，选项 StopWordLists 中其他的名字被忽略
+func (s *Segment) StopWordListNames() []string {
+	return s.stopWord.ListNames()
+}
+
 func (s *Segment) DoSegment(text string) *list.List {
 	return s.DoSegmentWithOptionParam(text, nil, nil)
 }
@@ -80,12 +146,52 @@ func (s *Segment) DoSegmentWithOption(text string, params *match.MatchOptions) *
 	return s.DoSegmentWithOptionParam(text, params, nil)
 }
 
+// 选项不正确时（如 Matcher 是没有注册的匹配器）返回空的结果，需要知道错误时用 SegmentText
 func (s *Segment) DoSegmentWithOptionParam(text string, options *match.MatchOptions, params *match.MatchParameter) *list.List {
+	result, _ := s.SegmentText(text, options, params)
+	return result
+}
 
+// 按选项分词，选项不正确时返回错误，如 Matcher 是没有注册的匹配器
+func (s *Segment) SegmentText(text string, options *match.MatchOptions, params *match.MatchParameter) (*list.List, error) {
 	if len(text) == 0 {
-		return list.New()
+		return list.New(), nil
+	}
+
+	if err := s.setOptionParams(options, params); err != nil {
+		return list.New(), err
+	}
+
+	var offsets []int
+	if function := s.normalizeFunction(); function != 0 {
+		text, offsets = s.normalizer.Normalize(text, function)
 	}
+	if s.explanation != nil {
+		s.explanation.Text = text
+	}
+
+	result := s.preSegment(text)
+	setOriginalLength(result)
+	if s.options.PosTagging && s.posTagger != nil {
+		s.tagPos(result)
+	}
+	if s.options.FilterStopWords {
+		s.filterStopWord(result)
+	}
+	if s.options.IncludePos != 0 || s.options.ExcludePos != 0 {
+		s.filterPos(result)
+	}
+	s.processAfterSegment(text, result)
 
+	if offsets != nil {
+		s.restorePosition(result, offsets)
+	}
+
+	return result, nil
+}
+
+// 设置本次分词的选项和参数，为空时使用默认值。选择的匹配器在这里检查，没有注册时返回错误
+func (s *Segment) setOptionParams(options *match.MatchOptions, params *match.MatchParameter) error {
 	s.options = options
 	s.params = params
 
@@ -96,19 +202,79 @@ func (s *Segment) DoSegmentWithOptionParam(text string, options *match.MatchOpti
 	if s.params == nil {
 		s.params = match.NewMatchParameter()
 	}
+	s.chsName.Threshold = float64(s.params.ChineseNameThreshold) / 100
 
-	result := s.preSegment(text)
-	if s.options.FilterStopWords {
-		s.filterStopWord(result)
+	// 人名形式的识别器只在启用的形式改变时重新创建
+	if patterns := s.namePatterns(); patterns == 0 {
+		s.namePattern = nil
+	} else if s.namePattern == nil || s.namePattern.Patterns() != patterns {
+		s.namePattern = s.chsName.Patterns(s.wordDictionary, patterns)
 	}
-	s.processAfterSegment(text, result)
 
-	return result
+	matcher, err := match.LookupMatcher(s.options)
+	if err != nil {
+		return err
+	}
+	s.matcher = matcher
+	return nil
+}
+
+// 用本次分词选择的匹配器创建新的匹配器
+func (s *Segment) newMatcher(options *match.MatchOptions) match.IChsFullTextMatch {
+	m := s.matcher(s.wordDictionary)
+	m.SetOptionParams(options, s.params)
+	return m
+}
+
+func (s *Segment) normalizeFunction() int {
+	function := 0
+	if s.options.NormalizeWidth {
+		function |= framework.NormalizeWidth
+	}
+	if s.options.NormalizePunctuation {
+		function |= framework.NormalizePunctuation
+	}
+	if s.options.NormalizeVariant {
+		function |= framework.NormalizeVariant
+	}
+	if s.options.NormalizeCase {
+		function |= framework.NormalizeCase
+	}
+	return function
+}
+
+// 没有单独设置 OriginalLength 的词，在文本中的字符数就是词本身的长度。
+// 词干、去掉重音的形式和同义词等和原文不同的词，在生成时已经设置为原来那个词或短语的长度
+func setOriginalLength(wordInfoList *list.List) {
+	for cur := wordInfoList.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		if wi.OriginalLength == 0 {
+			wi.OriginalLength = utils.RuneLen(wi.Word)
+		}
+	}
+}
+
+// 把归一化之后文本中的位置和长度映射回原文。
+// 一个字符归一化成多个字符时（如 … => ...），词只覆盖其中一部分的按整个原字符计算
+func (s *Segment) restorePosition(wordInfoList *list.List, offsets []int) {
+	last := len(offsets) - 1
+	for cur := wordInfoList.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		end := utils.IntMin(wi.Position+wi.OriginalLength, last)
+		for end < last && end > wi.Position && offsets[end] == offsets[end-1] {
+			end++
+		}
+		wi.OriginalLength = offsets[end] - offsets[wi.Position]
+		wi.Position = offsets[wi.Position]
+	}
 }
 
 func (s *Segment) preSegment(text string) *list.List {
 	result := s.getInitSegment(text)
 	runes := utils.ToRunes(text)
+	if s.options.ForeignNameIdentify {
+		s.joinForeignNames(result)
+	}
 	cur := result.Front()
 	for cur != nil {
 		if s.options.IgnoreSpace {
@@ -123,10 +289,30 @@ func (s *Segment) preSegment(text string) *list.List {
 		case dict.TSimplifiedChinese:
 			inputText := cur.Value.(*dict.WordInfo).Word
 			originalWordType := dict.TSimplifiedChinese
-			pls := s.wordDictionary.GetAllMatchs(inputText, s.options.ChineseNameIdentify)
-			chsMatch := match.NewChsFullTextMatch(s.wordDictionary)
-			chsMatch.SetOptionParams(s.options, s.params)
+			pls := s.wordDictionary.GetAllMatchs(inputText, s.options.ChineseNameIdentify, s.recognizers()...)
+			matchOptions := s.options
+			if s.options.QueryMode {
+				matchOptions = s.queryOptions()
+			}
+			chsMatch := s.newMatcher(matchOptions)
+			var trace *match.MatchTrace
+			if s.explanation != nil {
+				trace = match.NewMatchTrace(inputText, cur.Value.(*dict.WordInfo).Position, match.MatcherName(s.options), pls)
+				if t, ok := chsMatch.(match.Traceable); ok {
+					t.SetTrace(trace)
+				}
+			}
 			chsMatchWords := chsMatch.Match(pls, inputText)
+			if trace != nil {
+				trace.SetResult(chsMatchWords)
+				s.explanation.Blocks = append(s.explanation.Blocks, trace)
+			}
+			if s.options.QueryMode {
+				s.mergeQueryWords(chsMatchWords, pls, inputText)
+			}
+			if s.options.IndexMode {
+				s.addSubWords(chsMatchWords)
+			}
 			curChsMatch := chsMatchWords.Front()
 			for curChsMatch != nil {
 				wi := curChsMatch.Value.(*dict.WordInfo)
@@ -140,24 +326,40 @@ func (s *Segment) preSegment(text string) *list.List {
 			cur = rcur.Next()
 			result.Remove(removeItem)
 		case dict.TEnglish:
+		    // 小写、词干等形式和原词对应原文中同一段字符
+		    span := utils.RuneLen(cur.Value.(*dict.WordInfo).Word)
+		    cur.Value.(*dict.WordInfo).OriginalLength = span
 		    cur.Value.(*dict.WordInfo).Rank = s.params.EnglishRank
 		    cur.Value.(*dict.WordInfo).Word = s.convertChineseCapicalToAsiic(cur.Value.(*dict.WordInfo).Word)
 		    if s.options.IgnoreCapital {
-		        cur.Value.(*dict.WordInfo).Word = strings.ToLower(cur.Value.(*dict.WordInfo).Word)
+		        cur.Value.(*dict.WordInfo).Word = utils.FoldCase(cur.Value.(*dict.WordInfo).Word)
 		    }
 		    
 		    if s.options.EnglishSegment {
-		        lower := strings.ToLower(cur.Value.(*dict.WordInfo).Word)
+		        lower := utils.FoldCase(cur.Value.(*dict.WordInfo).Word)
 		        if lower != cur.Value.(*dict.WordInfo).Word {
-		            result.InsertBefore(dict.NewWordInfo(lower, cur.Value.(*dict.WordInfo).Position, dict.POS_A_NX, 1, s.params.EnglishLowerRank, dict.TEnglish, dict.TEnglish), cur)
+		            wi := dict.NewWordInfo(lower, cur.Value.(*dict.WordInfo).Position, dict.POS_A_NX, 1, s.params.EnglishLowerRank, dict.TEnglish, dict.TEnglish)
+		            wi.OriginalLength = span
+		            result.InsertBefore(wi, cur)
 		        }
 		        stem := s.getStem(lower)
 		        if len(stem) > 0 {
 		            if lower != stem {
-		                result.InsertBefore(dict.NewWordInfo(stem, cur.Value.(*dict.WordInfo).Position, dict.POS_A_NX, 1, s.params.EnglishStemRank, dict.TEnglish, dict.TEnglish), cur)
+		                wi := dict.NewWordInfo(stem, cur.Value.(*dict.WordInfo).Position, dict.POS_A_NX, 1, s.params.EnglishStemRank, dict.TEnglish, dict.TEnglish)
+		                wi.OriginalLength = span
+		                result.InsertBefore(wi, cur)
 		            }
 		        }
 		    }
+
+		    if s.options.AccentFolding {
+		        folded := utils.FoldAccent(cur.Value.(*dict.WordInfo).Word)
+		        if folded != cur.Value.(*dict.WordInfo).Word {
+		            wi := dict.NewWordInfo(folded, cur.Value.(*dict.WordInfo).Position, dict.POS_A_NX, 1, s.params.AccentFoldingRank, dict.TEnglish, dict.TEnglish)
+		            wi.OriginalLength = span
+		            result.InsertBefore(wi, cur)
+		        }
+		    }
 		    
 		    if s.options.EnglishMultiDimensionality {
 		        needSplit := false
@@ -214,6 +416,18 @@ func (s *Segment) preSegment(text string) *list.List {
 		case dict.TSymbol:
 			cur.Value.(*dict.WordInfo).Rank = s.params.SymbolRank
 			cur = cur.Next()
+		case dict.TKana:
+			cur.Value.(*dict.WordInfo).Rank = s.params.KanaRank
+			cur = s.matchKatakana(result, cur).Next()
+		case dict.THangul:
+			cur.Value.(*dict.WordInfo).Rank = s.params.HangulRank
+			cur = cur.Next()
+		case dict.TEmoji:
+			cur.Value.(*dict.WordInfo).Rank = s.params.EmojiRank
+			if s.options.EmojiAnnotation {
+				cur.Value.(*dict.WordInfo).Annotation = s.emoji.GetName(cur.Value.(*dict.WordInfo).Word)
+			}
+			cur = cur.Next()
 		default:
 			cur = cur.Next()
 		}
@@ -221,10 +435,48 @@ func (s *Segment) preSegment(text string) *list.List {
 	return result
 }
 
+// 按选项启用的专名识别器
+func (s *Segment) recognizers() []dict.Recognizer {
+	recognizers := []dict.Recognizer{}
+	if s.namePattern != nil {
+		recognizers = append(recognizers, s.namePattern)
+	}
+	if s.options.ForeignNameIdentify {
+		recognizers = append(recognizers, s.foreignName)
+	}
+	if s.options.PlaceNameIdentify {
+		recognizers = append(recognizers, s.placeName)
+	}
+	if s.options.OrgNameIdentify {
+		recognizers = append(recognizers, s.orgName)
+	}
+	return recognizers
+}
+
+func (s *Segment) namePatterns() (patterns int) {
+	if s.options.ChineseNameFourChars {
+		patterns |= dict.NamePatternFourChars
+	}
+	if s.options.ChineseNamePrefix {
+		patterns |= dict.NamePatternPrefix
+	}
+	if s.options.ChineseNameTitle {
+		patterns |= dict.NamePatternTitle
+	}
+	return
+}
+
 func (s *Segment) getStem(word string) string {
     if stem, ok := s.verbTable[word]; ok {
         return stem
     }
+
+    // porter 算法只适用于英文
+    for _, r := range word {
+        if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+            return ""
+        }
+    }
     
     st := framework.NewStemmer()
     for _, r := range word {
@@ -285,6 +537,51 @@ func (s *Segment) mergeEnglishSpecialWord(orginalText []rune, wordInfoList *list
    return false, current
 }
 
+// 片假名一般是外来语，先按整词查词典，查不到时尝试用词典中的词完整覆盖，
+// 不能完整覆盖的保持原样输出
+func (s *Segment) matchKatakana(wordInfoList *list.List, current *list.Element) *list.Element {
+	wi := current.Value.(*dict.WordInfo)
+	runes := utils.ToRunes(wi.Word)
+	katakana := false
+	for _, r := range runes {
+		if unicode.Is(unicode.Katakana, r) {
+			katakana = true
+			break
+		}
+	}
+	if !katakana {
+		return current
+	}
+
+	if wa := s.wordDictionary.GetWordAttr(runes); wa != nil {
+		wi.Pos = wa.Pos
+		wi.Frequency = wa.Frequency
+		return current
+	}
+
+	pls := s.wordDictionary.GetAllMatchs(wi.Word, false)
+	if len(pls) == 0 {
+		return current
+	}
+	words := s.newMatcher(s.options).Match(pls, wi.Word)
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		if cur.Value.(*dict.WordInfo).WordType == dict.TNone {
+			return current
+		}
+	}
+
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		w := cur.Value.(*dict.WordInfo)
+		w.Position += wi.Position
+		w.WordType = dict.TKana
+		w.OriginalWordType = dict.TKana
+		w.Rank = s.params.KanaRank
+	}
+	last := utils.InsertAfterList(wordInfoList, words, current)
+	wordInfoList.Remove(current)
+	return last
+}
+
 func (s *Segment) convertChineseCapicalToAsiic(text string) string {
     runes := utils.ToRunes(text)
     for i := 0; i < len(runes); i++ {
@@ -309,6 +606,16 @@ func (s *Segment) getInitSegment(text string) *list.List {
 	var dfaResult int
 
 	for i := 0; i < len(runes); i++ {
+		// 颜文字由多个符号组成，在词法分析之前整体匹配
+		if lexical.CurrentState == 0 {
+			if l := s.emoji.MatchEmoticon(runes, i); l > 0 {
+				result.PushBack(dict.NewWordInfo(string(runes[i:i+l]), i, dict.POS_UNK, 0, 0, dict.TEmoji, dict.TEmoji))
+				i += l - 1
+				lexical.Skip(i + 1)
+				continue
+			}
+		}
+
 		dfaResult = lexical.Input(runes[i], i)
 		switch dfaResult {
 		case framework.Continue:
@@ -317,9 +624,7 @@ func (s *Segment) getInitSegment(text string) *list.List {
 			result.PushBack(lexical.OutputToken)
 		case framework.ElseQuit:
 			result.PushBack(lexical.OutputToken)
-			if lexical.OldState != 255 {
-				i--
-			}
+			i--
 		}
 	}
 
@@ -338,9 +643,20 @@ func (s *Segment) filterStopWord(wordInfoList *list.List) {
 	if wordInfoList == nil {
 		return
 	}
+	lists := []string{dict.DefaultStopWordList}
+	if len(s.options.StopWordLists) > 0 {
+		lists = s.options.StopWordLists
+	}
+	extra := dict.NewStopWord()
+	extra.AddList(dict.DefaultStopWordList, s.options.ExtraStopWords)
+
 	cur := wordInfoList.Front()
 	for cur != nil {
-		if s.stopWord.IsStopWord(cur.Value.(*dict.WordInfo).Word, s.options.FilterEnglish, s.params.FilterEnglishLength, s.options.FilterNumeric, s.params.FilterNumericLength) {
+		wi := cur.Value.(*dict.WordInfo)
+		pos := wi.EffectivePos()
+		if dict.IsTooLong(wi.Word, s.options.FilterEnglish, s.params.FilterEnglishLength, s.options.FilterNumeric, s.params.FilterNumericLength) ||
+			s.stopWord.InLists(wi.Word, lists) || extra.InLists(wi.Word, []string{dict.DefaultStopWordList}) ||
+			(s.options.StopPos != 0 && pos != dict.POS_UNK && pos&^s.options.StopPos == 0) {
 			remoteItem := cur
 			cur = cur.Next()
 			wordInfoList.Remove(remoteItem)
@@ -350,20 +666,25 @@ func (s *Segment) filterStopWord(wordInfoList *list.List) {
 	}
 }
 
+// 按词性过滤，打开词性标注时每个词只有一个词性，否则按词典中所有可能的词性判断，见 WordInfo.EffectivePos
+func (s *Segment) filterPos(wordInfoList *list.List) {
+	cur := wordInfoList.Front()
+	for cur != nil {
+		pos := cur.Value.(*dict.WordInfo).EffectivePos()
+		if (s.options.IncludePos != 0 && pos&s.options.IncludePos == 0) || pos&s.options.ExcludePos != 0 {
+			removeItem := cur
+			cur = cur.Next()
+			wordInfoList.Remove(removeItem)
+		} else {
+			cur = cur.Next()
+		}
+	}
+}
+
 func (s *Segment) processAfterSegment(text string, result *list.List) {
 	// 匹配同义词
 	if s.options.SynonymOutput {
-		node := result.Front()
-		for node != nil {
-			pW := node.Value.(*dict.WordInfo)
-			synonyms := s.synonym.GetSynonyms(pW.Word)
-			if synonyms != nil {
-				for _, word := range synonyms {
-					node = result.InsertAfter(dict.NewWordInfo(word, pW.Position, pW.Pos, pW.Frequency, s.params.SymbolRank, dict.TSynonym, pW.WordType), node)
-				}
-			}
-			node = node.Next()
-		}
+		s.addSynonyms(text, result)
 	}
 
 	// 通配符匹配
@@ -0,0 +1,112 @@
+package segment
+
+import (
+	"segment/dict"
+	"segment/match"
+	"testing"
+)
+
+func TestStopWordLists(t *testing.T) {
+	text := "我们今天的天气很好，The weather is nice！"
+	cases := []struct {
+		lists    []string
+		expected string
+	}{
+		{nil, "我们/今天/的/天气/很好/The/weather/is/nice"},
+		{[]string{"zh"}, "今天/天气/很好/，/The/weather/is/nice/！"},
+		{[]string{"default", "zh", "en", "punct"}, "今天/天气/很好/weather/nice"},
+	}
+	s := loadTestSegment(t)
+	for _, c := range cases {
+		options := match.NewMatchOptions()
+		options.MultiDimensionality = false
+		options.StopWordLists = c.lists
+		if got := segmentString(s, text, options); got != c.expected {
+			t.Errorf("%v: got %s, want %s", c.lists, got, c.expected)
+		}
+	}
+}
+
+// 没有加载的停用词表被忽略，不影响其他表
+func TestUnknownStopWordList(t *testing.T) {
+	s := loadTestSegment(t)
+	options := match.NewMatchOptions()
+	options.StopWordLists = []string{"nonexistent"}
+	if got := segmentString(s, "你好！", options); got != "你好/！" {
+		t.Errorf("got %s, want 你好/！", got)
+	}
+	options.StopWordLists = []string{"nonexistent", "punct"}
+	if got := segmentString(s, "你好！", options); got != "你好" {
+		t.Errorf("got %s, want 你好", got)
+	}
+}
+
+// 额外的停用词只对本次分词有效，英文不区分大小写
+func TestExtraStopWords(t *testing.T) {
+	s := loadTestSegment(t)
+	text := "我们今天的天气很好，The weather is nice！"
+	options := match.NewMatchOptions()
+	options.MultiDimensionality = false
+	options.ExtraStopWords = []string{"天气", "WEATHER"}
+	if got, expected := segmentString(s, text, options), "我们/今天/的/很好/The/is/nice"; got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+	options.ExtraStopWords = nil
+	if got, expected := segmentString(s, text, options), "我们/今天/的/天气/很好/The/weather/is/nice"; got != expected {
+		t.Errorf("got %s, want %s", got, expected)
+	}
+}
+
+// 只有停用词性的词被去掉，兼有其他词性的 八哥(m|n) 保留。不使用停用词表，只按词性过滤
+func TestStopPos(t *testing.T) {
+	s := loadTestSegment(t)
+	options := match.NewMatchOptions()
+	options.MultiDimensionality = false
+	options.StopWordLists = []string{"nonexistent"}
+	options.StopPos = dict.POS_A_M | dict.POS_D_U | dict.POS_D_W
+	cases := map[string]string{
+		"两只八哥，":      "只/八哥",
+		"我们今天的天气很好！": "我们/今天/天气/很好",
+	}
+	for text, expected := range cases {
+		if got := segmentString(s, text, options); got != expected {
+			t.Errorf("%s: got %s, want %s", text, got, expected)
+		}
+	}
+}
+
+// 没有注册的匹配器由 SegmentText 返回错误，DoSegment 不会 panic
+func TestUnknownMatcher(t *testing.T) {
+	s := loadTestSegment(t)
+	options := match.NewMatchOptions()
+	options.Matcher = "nonexistent"
+	if _, err := s.SegmentText("长春市长春节讲话", options, nil); err == nil {
+		t.Errorf("expected an error for an unknown matcher")
+	}
+	if words := s.DoSegmentWithOption("长春市长春节讲话", options); words.Len() != 0 {
+		t.Errorf("expected no words for an unknown matcher, got %d", words.Len())
+	}
+
+	// 之后的分词不受影响
+	options.Matcher = match.ForwardMatcher
+	if got := segmentString(s, "长春市长春节讲话", options); got == "" {
+		t.Errorf("segmentation failed after an unknown matcher")
+	}
+}
+
+// 分词中途 panic 时 Explain 也要清除记录，否则之后的每次分词都会继续往里面添加
+func TestExplainResetsAfterPanic(t *testing.T) {
+	s := loadTestSegment(t)
+	match.RegisterMatcher("panic", func(wdict *dict.WordDictionary) match.IChsFullTextMatch {
+		panic("matcher failed")
+	})
+	options := match.NewMatchOptions()
+	options.Matcher = "panic"
+	func() {
+		defer func() { recover() }()
+		s.Explain("长春市长春节讲话", options, nil)
+	}()
+	if s.explanation != nil {
+		t.Fatalf("explanation is still set after Explain panicked")
+	}
+}
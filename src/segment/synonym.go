@@ -0,0 +1,84 @@
+package segment
+
+import (
+	"container/list"
+	"segment/dict"
+	"segment/utils"
+	"unicode"
+)
+
+// 导入 Solr/Elasticsearch 格式的同义词文件，补充到词典目录中的同义词规则中
+func (s *Segment) ImportSynonyms(file string) error {
+	return s.synonym.Import(file)
+}
+
+// 输出同义词：从每个词开始，和后面紧挨着的几个词拼起来与同义词规则匹配，
+// 词之间可以隔着空白，如 new york。扩展出来的同义词插在短语最后一个词的后面，
+// 位置是短语的开始位置，OriginalLength 是短语的字符数，Score 是规则的权重
+func (s *Segment) addSynonyms(text string, result *list.List) {
+	runes := utils.ToRunes(text)
+	maxLength := s.synonym.MaxKeyLength()
+	// 英文的原词和小写形式都会匹配，同一段文本的同一个同义词只输出一次
+	type span struct {
+		word  string
+		start int
+		end   int
+	}
+	added := make(map[span]bool)
+	for node := result.Front(); node != nil; node = node.Next() {
+		first := node.Value.(*dict.WordInfo)
+		if first.WordType == dict.TSynonym {
+			continue
+		}
+
+		phrase := ""
+		end := first.Position
+		for cur, count := node, 1; cur != nil; count++ {
+			wi := cur.Value.(*dict.WordInfo)
+			phrase += wi.Word
+			end = wi.Position + wi.OriginalLength
+			if utils.RuneLen(phrase) > maxLength {
+				break
+			}
+
+			mark := cur
+			for _, syn := range s.synonym.GetWeightedSynonyms(phrase) {
+				if added[span{syn.Word, first.Position, end}] {
+					continue
+				}
+				added[span{syn.Word, first.Position, end}] = true
+				w := dict.NewWordInfo(syn.Word, first.Position, first.Pos, first.Frequency, s.params.SynonymRank, dict.TSynonym, first.WordType)
+				if count > 1 {
+					w.Pos = dict.POS_UNK
+					w.Frequency = 0
+				}
+				w.OriginalLength = end - first.Position
+				w.Score = syn.Weight
+				mark = result.InsertAfter(w, mark)
+			}
+			cur = nextAdjacent(cur, skipSpaces(runes, end))
+		}
+	}
+}
+
+// cur 后面从 position 开始的第一个词，多元分词时中间可能隔着其他的词
+func nextAdjacent(cur *list.Element, position int) *list.Element {
+	for next := cur.Next(); next != nil; next = next.Next() {
+		wi := next.Value.(*dict.WordInfo)
+		if wi.WordType == dict.TSynonym || wi.Position < position {
+			continue
+		}
+		if wi.Position == position {
+			return next
+		}
+		return nil
+	}
+	return nil
+}
+
+func skipSpaces(runes []rune, position int) int {
+	for position < len(runes) && unicode.IsSpace(runes[position]) {
+		position++
+	}
+	return position
+}
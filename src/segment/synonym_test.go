@@ -0,0 +1,60 @@
+package segment
+
+import (
+	"fmt"
+	"segment/dict"
+	"segment/match"
+	"strings"
+	"testing"
+)
+
+// 临时替换测试用 Segment 的同义词规则，返回恢复原规则的函数
+func useTestSynonyms(s *Segment, lines ...string) func() {
+	old := s.synonym
+	s.synonym = dict.NewSynonym()
+	for _, line := range lines {
+		s.synonym.AddRule(line)
+	}
+	return func() { s.synonym = old }
+}
+
+// 同义词输出为 词@位置:原文长度:权值:权重
+func synonymWords(t *testing.T, s *Segment, text string, params *match.MatchParameter) string {
+	options := match.NewMatchOptions()
+	options.SynonymOutput = true
+	words, err := s.SegmentText(text, options, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := []string{}
+	for cur := words.Front(); cur != nil; cur = cur.Next() {
+		wi := cur.Value.(*dict.WordInfo)
+		if wi.WordType == dict.TSynonym {
+			result = append(result, fmt.Sprintf("%s@%d:%d:%d:%g", wi.Word, wi.Position, wi.OriginalLength, wi.Rank, wi.Score))
+		}
+	}
+	return strings.Join(result, "/")
+}
+
+func TestSynonymOutput(t *testing.T) {
+	s := loadTestSegment(t)
+	defer useTestSynonyms(s, "揭穿,戳穿", "揭穿,揭露|0.5", "北大 => 北京大学", "new york, 纽约|0.8")()
+
+	params := match.NewMatchParameter()
+	params.SynonymRank = 7
+	cases := map[string]string{
+		// 一个词在两组同义词中
+		"他揭穿了谎言": "戳穿@1:2:7:1/揭露@1:2:7:0.5",
+		// 单向规则
+		"我在北大读书":   "北京大学@2:2:7:1",
+		"我在北京大学读书": "",
+		// 多个词组成的短语，词之间隔着空白
+		"我在New York工作": "纽约@2:8:7:0.8",
+		"我在纽约工作":       "new york@2:2:7:0.8",
+	}
+	for text, expected := range cases {
+		if got := synonymWords(t, s, text, params); got != expected {
+			t.Errorf("%s: got %s, want %s", text, got, expected)
+		}
+	}
+}
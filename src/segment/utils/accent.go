@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// 带重音符号的拉丁字母和希腊字母到基本字母的映射，两个字符串中的字符一一对应
+const (
+	accentFrom = "ÀÁÂÃÄÅÇÈÉÊËÌÍÎÏÐÑÒÓÔÕÖØÙ" +
+		"ÚÛÜÝàáâãäåçèéêëìíîïðñòóô" +
+		"õöøùúûüýÿĀāĂăĄąĆćĈĉĊċČčĎ" +
+		"ďĐđĒēĔĕĖėĘęĚěĜĝĞğĠġĢģĤĥĦ" +
+		"ħĨĩĪīĬĭĮįİıĴĵĶķĹĺĻļĽľŁłŃ" +
+		"ńŅņŇňŊŋŌōŎŏŐőŔŕŖŗŘřŚśŜŝŞ" +
+		"şŠšŢţŤťŦŧŨũŪūŬŭŮůŰűŲųŴŵŶ" +
+		"ŷŸŹźŻżŽžƒƠơƯưǍǎǏǐǑǒǓǔǕǖǗ" +
+		"ǘǙǚǛǜǞǟǠǡǢǣǦǧǨǩǪǫǬǭǮǯǰǴǵ" +
+		"ǸǹǺǻǼǽǾǿȀȁȂȃȄȅȆȇȈȉȊȋȌȍȎȏ" +
+		"ȐȑȒȓȔȕȖȗȘșȚțȞȟȦȧȨȩȪȫȬȭȮȯ" +
+		"ȰȱȲȳΆΈΉΊΌΎΏΐΪΫάέήίΰϊϋόύώ" +
+		"ḀḁḂḃḄḅḆḇḈḉḊḋḌḍḎḏḐḑḒḓḔḕḖḗ" +
+		"ḘḙḚḛḜḝḞḟḠḡḢḣḤḥḦḧḨḩḪḫḬḭḮḯ" +
+		"ḰḱḲḳḴḵḶḷḸḹḺḻḼḽḾḿṀṁṂṃṄṅṆṇ" +
+		"ṈṉṊṋṌṍṎṏṐṑṒṓṔṕṖṗṘṙṚṛṜṝṞṟ" +
+		"ṠṡṢṣṤṥṦṧṨṩṪṫṬṭṮṯṰṱṲṳṴṵṶṷ" +
+		"ṸṹṺṻṼṽṾṿẀẁẂẃẄẅẆẇẈẉẊẋẌẍẎẏ" +
+		"ẐẑẒẓẔẕẖẗẘẙẛẠạẢảẤấẦầẨẩẪẫẬ" +
+		"ậẮắẰằẲẳẴẵẶặẸẹẺẻẼẽẾếỀềỂểỄ" +
+		"ễỆệỈỉỊịỌọỎỏỐốỒồỔổỖỗỘộỚớỜ" +
+		"ờỞởỠỡỢợỤụỦủỨứỪừỬửỮữỰựỲỳỴ" +
+		"ỵỶỷỸỹ"
+	accentTo = "AAAAAACEEEEIIIIDNOOOOOOU" +
+		"UUUYaaaaaaceeeeiiiidnooo" +
+		"ooouuuuyyAaAaAaCcCcCcCcD" +
+		"dDdEeEeEeEeEeGgGgGgGgHhH" +
+		"hIiIiIiIiIiJjKkLlLlLlLlN" +
+		"nNnNnNnOoOoOoRrRrRrSsSsS" +
+		"sSsTtTtTtUuUuUuUuUuUuWwY" +
+		"yYZzZzZzfOoUuAaIiOoUuUuU" +
+		"uUuUuAaAaÆæGgKkOoOoƷʒjGg" +
+		"NnAaÆæØøAaAaEeEeIiIiOoOo" +
+		"RrRrUuUuSsTtHhAaEeOoOoOo" +
+		"OoYyΑΕΗΙΟΥΩιΙΥαεηιυιυουω" +
+		"AaBbBbBbCcDdDdDdDdDdEeEe" +
+		"EeEeEeFfGgHhHhHhHhHhIiIi" +
+		"KkKkKkLlLlLlLlMmMmMmNnNn" +
+		"NnNnOoOoOoOoPpPpRrRrRrRr" +
+		"SsSsSsSsSsTtTtTtTtUuUuUu" +
+		"UuUuVvVvWwWwWwWwWwXxXxYy" +
+		"ZzZzZzhtwyſAaAaAaAaAaAaA" +
+		"aAaAaAaAaAaEeEeEeEeEeEeE" +
+		"eEeIiIiOoOoOoOoOoOoOoOoO" +
+		"oOoOoOoUuUuUuUuUuUuUuYyY" +
+		"yYyYy"
+)
+
+// 不能映射到单个字母的连字
+var accentLigature = map[rune]string{
+	'Æ': "AE",
+	'Þ': "TH",
+	'ß': "ss",
+	'æ': "ae",
+	'þ': "th",
+	'Œ': "OE",
+	'œ': "oe",
+}
+
+var accentDict map[rune]rune
+
+func init() {
+	accentDict = make(map[rune]rune)
+	to := ToRunes(accentTo)
+	for i, r := range ToRunes(accentFrom) {
+		accentDict[r] = to[i]
+	}
+}
+
+// 去掉重音符号，café => cafe，同时去掉分解形式中的组合附加符
+func FoldAccent(s string) string {
+	result := make([]rune, 0, len(s))
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if base, ok := accentDict[r]; ok {
+			r = base
+		}
+		if ligature, ok := accentLigature[r]; ok {
+			result = append(result, ToRunes(ligature)...)
+			continue
+		}
+		result = append(result, r)
+	}
+	return string(result)
+}
+
+// unicode 大小写折叠，和 strings.ToLower 不同，希腊语词尾的 ς 会和 σ 折叠成同一个字母
+func FoldCase(s string) string {
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, s)
+}
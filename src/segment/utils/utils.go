@@ -39,7 +39,8 @@ func EachLine(file string, handle func(string)) error {
 	}
 	defer f.Close()
 
-	bf := bufio.NewReader(f)
+	// 语料文件一行可能很长
+	bf := bufio.NewReaderSize(f, 1024*1024)
 	for {
 		line, isPrefix, err := bf.ReadLine()
 		if err == io.EOF {
@@ -71,3 +72,12 @@ func IntMin(a int, b int) int {
 	}
 	return a
 }
+
+// read text file line by line, a missing file is treated as empty
+func EachLineIfExist(file string, handle func(string)) error {
+	err := EachLine(file, handle)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
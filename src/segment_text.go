@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"segment"
+	"segment/dict"
+	"segment/match"
+)
+
+// 对命令行参数中的文本分词，没有参数时逐行读取标准输入
+func segmentText(args []string) error {
+	fs := flag.NewFlagSet("segment", flag.ExitOnError)
+	dicts := fs.String("dicts", "./dicts", "词典目录")
+	options := fs.String("options", "", "MatchOptions 开关，如 Matcher=forward,ChineseNameIdentify=false")
+	explain := fs.String("explain", "", "输出分词过程: json 或 dot (Graphviz 候选词网格)")
+	fs.Parse(args)
+
+	if *explain != "" && *explain != "json" && *explain != "dot" {
+		fs.Usage()
+		return errors.New("segment: -explain must be json or dot")
+	}
+
+	opt := match.NewMatchOptions()
+	if err := parseMatchOptions(*options, opt); err != nil {
+		return err
+	}
+	seg := segment.NewSegment()
+	if err := seg.Init(*dicts); err != nil {
+		return err
+	}
+	if err := checkStopWordLists(seg, opt); err != nil {
+		return err
+	}
+
+	handle := func(text string) error {
+		switch *explain {
+		case "json":
+			data, err := seg.Explain(text, opt, nil).JSON()
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(data))
+		case "dot":
+			fmt.Print(seg.Explain(text, opt, nil).Dot())
+		default:
+			for cur := seg.DoSegmentWithOption(text, opt).Front(); cur != nil; cur = cur.Next() {
+				w := cur.Value.(*dict.WordInfo)
+				fmt.Print(w.Word, "(", w.Position, ",", w.Rank, ")/")
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
+	if fs.NArg() > 0 {
+		for _, text := range fs.Args() {
+			if err := handle(text); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		if err := handle(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"segment/dict"
+)
+
+// 从分好词的语料统计词频和词性，生成或合并 Dict.txt
+func trainDict(args []string) error {
+	fs := flag.NewFlagSet("train", flag.ExitOnError)
+	base := fs.String("dict", "", "要合并的基础词典，为空时生成新词典")
+	output := fs.String("output", "", "词典输出文件，必须指定；没有 -dict 时不能覆盖已有的文件")
+	scale := fs.Float64("scale", 1, "语料词频乘以这个系数后再和基础词典的词频相加")
+	minFreq := fs.Float64("minfreq", 1, "语料中出现次数小于这个值的新词不写入词典")
+	fs.Usage = func() {
+		fs.Output().Write([]byte("usage: gosegment train [options] corpus...\n"))
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("train: missing corpus file")
+	}
+	if *output == "" {
+		fs.Usage()
+		return errors.New("train: missing -output")
+	}
+	// 只用语料生成的词典会丢掉原词典里的词，覆盖已有词典时必须用 -dict 指定要合并的基础词典
+	if _, err := os.Stat(*output); err == nil && *base == "" {
+		return errors.New("train: " + *output + " already exists, use -dict to merge with it or choose another -output")
+	}
+
+	trainer := dict.NewDictTrainer()
+	trainer.Scale = *scale
+	trainer.MinFreq = *minFreq
+	if *base != "" {
+		if err := trainer.LoadBase(*base); err != nil {
+			return err
+		}
+	}
+	for _, corpus := range fs.Args() {
+		if err := trainer.AddCorpus(corpus); err != nil {
+			return err
+		}
+	}
+	return trainer.Save(*output)
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"segment/match"
+)
+
+// 从分好词的语料训练未登录词识别用的 HMM 字标注模型
+func trainHmm(args []string) error {
+	fs := flag.NewFlagSet("train-hmm", flag.ExitOnError)
+	corpus := fs.String("corpus", "", "分好词的语料，词之间以空白分割，可以带 /词性")
+	output := fs.String("output", "", "模型输出文件，必须指定，不能覆盖已有的文件；使用时放到词典目录中，文件名为 "+match.UnknownWordModelFileName)
+	fs.Parse(args)
+
+	if *corpus == "" {
+		fs.Usage()
+		return errors.New("train-hmm: missing -corpus")
+	}
+	if *output == "" {
+		fs.Usage()
+		return errors.New("train-hmm: missing -output")
+	}
+	if _, err := os.Stat(*output); err == nil {
+		return errors.New("train-hmm: " + *output + " already exists, remove it or choose another -output")
+	}
+
+	model, err := match.TrainUnknownWordModel(*corpus)
+	if err != nil {
+		return err
+	}
+	return model.Save(*output)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"segment/dict"
+)
+
+// 从人民日报格式的语料训练人名打分用的位置概率模型
+func trainName(args []string) error {
+	fs := flag.NewFlagSet("train-name", flag.ExitOnError)
+	corpus := fs.String("corpus", "", "分好词并标注了词性的语料，格式为 词/词性，人名标注为 nr")
+	dicts := fs.String("dicts", "", "词典目录，用其中的 ChsFamilyName.txt 补充姓氏表，可以为空")
+	output := fs.String("output", "", "模型输出文件，必须指定，不能覆盖已有的文件；使用时放到词典目录中，文件名为 "+dict.ChsNameModelFileName)
+	fs.Parse(args)
+
+	if *corpus == "" {
+		fs.Usage()
+		return errors.New("train-name: missing -corpus")
+	}
+	if *output == "" {
+		fs.Usage()
+		return errors.New("train-name: missing -output")
+	}
+	if _, err := os.Stat(*output); err == nil {
+		return errors.New("train-name: " + *output + " already exists, remove it or choose another -output")
+	}
+
+	chsName := dict.NewChsName()
+	if *dicts != "" {
+		if err := chsName.LoadFamilyNames(*dicts); err != nil {
+			return err
+		}
+	}
+	model, err := dict.TrainNameModel(*corpus, chsName)
+	if err != nil {
+		return err
+	}
+	return model.Save(*output)
+}
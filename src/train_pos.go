@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"segment/dict"
+)
+
+// 从人民日报格式的语料训练 HMM 词性标注模型
+func trainPos(args []string) error {
+	fs := flag.NewFlagSet("train-pos", flag.ExitOnError)
+	corpus := fs.String("corpus", "", "分好词并标注了词性的语料，格式为 词/词性")
+	output := fs.String("output", "", "模型输出文件，必须指定，不能覆盖已有的文件；使用时放到词典目录中，文件名为 "+dict.PosModelFileName)
+	fs.Parse(args)
+
+	if *corpus == "" {
+		fs.Usage()
+		return errors.New("train-pos: missing -corpus")
+	}
+	if *output == "" {
+		fs.Usage()
+		return errors.New("train-pos: missing -output")
+	}
+	if _, err := os.Stat(*output); err == nil {
+		return errors.New("train-pos: " + *output + " already exists, remove it or choose another -output")
+	}
+
+	model, err := dict.TrainPosModel(*corpus)
+	if err != nil {
+		return err
+	}
+	return model.Save(*output)
+}